@@ -11,6 +11,8 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position // position of the first character belonging to the node
+	End() token.Position // position immediately after the node
 }
 
 // Statement implements the Node interface.
@@ -25,10 +27,23 @@ type Expression interface {
 	expressionNode()
 }
 
+// Pattern implements the Node interface.
+// Patterns bind parts of a value to identifiers, e.g. in "const [a, b] = arr;".
+// Patterns of match cases also compare parts of the value with literals.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
 // Program is the root of ast it holds a list of statements,
 // because that's what the program actually is if you think about it.
 type Program struct {
 	Statements []Statement
+	// Comments holds all comment groups of the program in the source order.
+	// It is filled only when the lexer scans comments, see lexer.ScanComments.
+	Comments []*CommentGroup
+	// CommentMap holds comments attached to the statements.
+	CommentMap CommentMap
 }
 
 // TokenLiteral returns root element of the AST tree.
@@ -39,6 +54,22 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+// Pos returns position of the first statement of the program.
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+
+// End returns position after the last statement of the program.
+func (p *Program) End() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[len(p.Statements)-1].End()
+	}
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 
@@ -51,9 +82,12 @@ func (p *Program) String() string {
 
 // ConstStatement is a AST node representing "const" token.
 type ConstStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Doc       *CommentGroup // comment group ending with a "/** */" doc comment placed right before the statement, may be nil
+	Token     token.Token
+	Name      *Identifier // nil if the value is destructured with Pattern
+	Pattern   Pattern     // array or hash pattern, nil if the value is bound to Name
+	Value     Expression
+	Semicolon token.Position // position of the ";"
 }
 
 func (cs *ConstStatement) statementNode() {}
@@ -63,11 +97,25 @@ func (cs *ConstStatement) TokenLiteral() string {
 	return cs.Token.Literal
 }
 
+// Pos returns position of the "const" keyword.
+func (cs *ConstStatement) Pos() token.Position {
+	return cs.Token.Pos
+}
+
+// End returns position after the ConstStatement.
+func (cs *ConstStatement) End() token.Position {
+	return statementEnd(cs.Semicolon, cs.Value, cs.Token)
+}
+
 func (cs *ConstStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(cs.TokenLiteral() + " ")
-	out.WriteString(cs.Name.String())
+	if cs.Pattern != nil {
+		out.WriteString(cs.Pattern.String())
+	} else {
+		out.WriteString(cs.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if cs.Value != nil {
@@ -79,10 +127,93 @@ func (cs *ConstStatement) String() string {
 	return out.String()
 }
 
+// LetStatement is a AST node representing "let" token.
+// Unlike the constant, the variable declared with it can be reassigned.
+type LetStatement struct {
+	Token     token.Token
+	Name      *Identifier
+	Value     Expression
+	Semicolon token.Position // position of the ";"
+}
+
+func (ls *LetStatement) statementNode() {}
+
+// TokenLiteral returns the LetStatement's token.
+func (ls *LetStatement) TokenLiteral() string {
+	return ls.Token.Literal
+}
+
+// Pos returns position of the "let" keyword.
+func (ls *LetStatement) Pos() token.Position {
+	return ls.Token.Pos
+}
+
+// End returns position after the LetStatement.
+func (ls *LetStatement) End() token.Position {
+	return statementEnd(ls.Semicolon, ls.Value, ls.Token)
+}
+
+func (ls *LetStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ls.TokenLiteral() + " ")
+	out.WriteString(ls.Name.String())
+	out.WriteString(" = ")
+
+	if ls.Value != nil {
+		out.WriteString(ls.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+// AssignStatement is a AST node representing assignment to a variable, e.g. x = x + 1;
+type AssignStatement struct {
+	Token     token.Token // "="
+	Name      *Identifier
+	Value     Expression
+	Semicolon token.Position // position of the ";"
+}
+
+func (as *AssignStatement) statementNode() {}
+
+// TokenLiteral returns the AssignStatement's token.
+func (as *AssignStatement) TokenLiteral() string {
+	return as.Token.Literal
+}
+
+// Pos returns position of the assigned variable.
+func (as *AssignStatement) Pos() token.Position {
+	return as.Name.Pos()
+}
+
+// End returns position after the AssignStatement.
+func (as *AssignStatement) End() token.Position {
+	return statementEnd(as.Semicolon, as.Value, as.Token)
+}
+
+func (as *AssignStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(as.Name.String())
+	out.WriteString(" = ")
+
+	if as.Value != nil {
+		out.WriteString(as.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
 // ReturnStatement is a AST node representing "return" token.
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
+	Semicolon   token.Position // position of the ";"
 }
 
 func (rs *ReturnStatement) statementNode() {}
@@ -92,6 +223,16 @@ func (rs *ReturnStatement) TokenLiteral() string {
 	return rs.Token.Literal
 }
 
+// Pos returns position of the "return" keyword.
+func (rs *ReturnStatement) Pos() token.Position {
+	return rs.Token.Pos
+}
+
+// End returns position after the ReturnStatement.
+func (rs *ReturnStatement) End() token.Position {
+	return statementEnd(rs.Semicolon, rs.ReturnValue, rs.Token)
+}
+
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 
@@ -111,6 +252,7 @@ func (rs *ReturnStatement) String() string {
 type ExpressionStatement struct {
 	Token      token.Token
 	Expression Expression
+	Semicolon  token.Position // position of the ";"
 }
 
 func (es *ExpressionStatement) statementNode() {}
@@ -120,6 +262,16 @@ func (es *ExpressionStatement) TokenLiteral() string {
 	return es.Token.Literal
 }
 
+// Pos returns position of the first token of the ExpressionStatement.
+func (es *ExpressionStatement) Pos() token.Position {
+	return es.Token.Pos
+}
+
+// End returns position after the ExpressionStatement.
+func (es *ExpressionStatement) End() token.Position {
+	return statementEnd(es.Semicolon, es.Expression, es.Token)
+}
+
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
 		return es.Expression.String()
@@ -130,8 +282,9 @@ func (es *ExpressionStatement) String() string {
 
 // BlockStatement holds multiple statements together
 type BlockStatement struct {
-	Token      token.Token
+	Token      token.Token // "{", or "if" of the block holding the nested if of "else if"
 	Statements []Statement
+	Rbrace     token.Position // position of the closing "}"
 }
 
 func (bs *BlockStatement) statementNode() {}
@@ -141,6 +294,16 @@ func (bs *BlockStatement) TokenLiteral() string {
 	return bs.Token.Literal
 }
 
+// Pos returns position of the opening "{".
+func (bs *BlockStatement) Pos() token.Position {
+	return bs.Token.Pos
+}
+
+// End returns position after the closing "}".
+func (bs *BlockStatement) End() token.Position {
+	return closingEnd(bs.Rbrace, bs.Token)
+}
+
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 
@@ -158,12 +321,23 @@ type Identifier struct {
 }
 
 func (i *Identifier) expressionNode() {}
+func (i *Identifier) patternNode()    {}
 
 // TokenLiteral returns the Identifier's token.
 func (i *Identifier) TokenLiteral() string {
 	return i.Token.Literal
 }
 
+// Pos returns position of the Identifier's token.
+func (i *Identifier) Pos() token.Position {
+	return i.Token.Pos
+}
+
+// End returns position after the Identifier's token.
+func (i *Identifier) End() token.Position {
+	return i.Token.End
+}
+
 func (i *Identifier) String() string {
 	return i.Value
 }
@@ -181,10 +355,47 @@ func (il *IntegerLiteral) TokenLiteral() string {
 	return il.Token.Literal
 }
 
+// Pos returns position of the IntegerLiteral's token.
+func (il *IntegerLiteral) Pos() token.Position {
+	return il.Token.Pos
+}
+
+// End returns position after the IntegerLiteral's token.
+func (il *IntegerLiteral) End() token.Position {
+	return il.Token.End
+}
+
 func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+// FloatLiteral is a AST node representing floating-point number token.
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+
+// TokenLiteral returns the FloatLiteral's token.
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+
+// Pos returns position of the FloatLiteral's token.
+func (fl *FloatLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
+
+// End returns position after the FloatLiteral's token.
+func (fl *FloatLiteral) End() token.Position {
+	return fl.Token.End
+}
+
+func (fl *FloatLiteral) String() string {
+	return fl.Token.Literal
+}
+
 // BooleanLiteral is a AST node representing boolean token.
 type BooleanLiteral struct {
 	Token token.Token
@@ -198,10 +409,64 @@ func (bl *BooleanLiteral) TokenLiteral() string {
 	return bl.Token.Literal
 }
 
+// Pos returns position of the BooleanLiteral's token.
+func (bl *BooleanLiteral) Pos() token.Position {
+	return bl.Token.Pos
+}
+
+// End returns position after the BooleanLiteral's token.
+func (bl *BooleanLiteral) End() token.Position {
+	return bl.Token.End
+}
+
 func (bl *BooleanLiteral) String() string {
 	return bl.Token.Literal
 }
 
+// InterpolatedString is a node representing a string with embedded "${...}" expressions.
+type InterpolatedString struct {
+	Token token.Token // STRING_HEAD
+	// string literals of the string's parts at even indexes alternating with the interpolated expressions,
+	// the first part comes from the STRING_HEAD token and the last one from the STRING_TAIL token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode() {}
+
+// TokenLiteral returns the literal of the string's first part.
+func (is *InterpolatedString) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+// Pos returns position of the opening quote.
+func (is *InterpolatedString) Pos() token.Position {
+	return is.Token.Pos
+}
+
+// End returns position after the closing quote.
+func (is *InterpolatedString) End() token.Position {
+	if len(is.Parts) > 0 {
+		return is.Parts[len(is.Parts)-1].End()
+	}
+	return is.Token.End
+}
+
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	for i, part := range is.Parts {
+		if i%2 == 0 {
+			out.WriteString(part.String())
+			continue
+		}
+		out.WriteString("${")
+		out.WriteString(part.String())
+		out.WriteString("}")
+	}
+
+	return out.String()
+}
+
 // StringLiteral is a node representing a string.
 type StringLiteral struct {
 	Token token.Token
@@ -215,6 +480,16 @@ func (sl *StringLiteral) TokenLiteral() string {
 	return sl.Token.Literal
 }
 
+// Pos returns position of the StringLiteral's token.
+func (sl *StringLiteral) Pos() token.Position {
+	return sl.Token.Pos
+}
+
+// End returns position after the StringLiteral's token.
+func (sl *StringLiteral) End() token.Position {
+	return sl.Token.End
+}
+
 func (sl *StringLiteral) String() string {
 	return sl.Token.Literal
 }
@@ -233,6 +508,16 @@ func (pe *PrefixExpression) TokenLiteral() string {
 	return pe.Token.Literal
 }
 
+// Pos returns position of the prefix operator.
+func (pe *PrefixExpression) Pos() token.Position {
+	return pe.Token.Pos
+}
+
+// End returns position after the right operand.
+func (pe *PrefixExpression) End() token.Position {
+	return expressionEnd(pe.Right, pe.Token)
+}
+
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 
@@ -259,6 +544,19 @@ func (ie *InfixExpression) TokenLiteral() string {
 	return ie.Token.Literal
 }
 
+// Pos returns position of the left operand.
+func (ie *InfixExpression) Pos() token.Position {
+	if ie.Left != nil {
+		return ie.Left.Pos()
+	}
+	return ie.Token.Pos
+}
+
+// End returns position after the right operand.
+func (ie *InfixExpression) End() token.Position {
+	return expressionEnd(ie.Right, ie.Token)
+}
+
 func (ie *InfixExpression) String() string {
 	var out bytes.Buffer
 
@@ -272,6 +570,7 @@ func (ie *InfixExpression) String() string {
 }
 
 // IfStatement is a AST node representing if statement // if (a < b) { print(a); } else { print(b); }
+// In the "else if" chain the Alternative holds only the nested IfStatement.
 type IfStatement struct {
 	Token       token.Token
 	Condition   Expression
@@ -286,6 +585,22 @@ func (is *IfStatement) TokenLiteral() string {
 	return is.Token.Literal
 }
 
+// Pos returns position of the "if" keyword.
+func (is *IfStatement) Pos() token.Position {
+	return is.Token.Pos
+}
+
+// End returns position after the last block of the IfStatement.
+func (is *IfStatement) End() token.Position {
+	if is.Alternative != nil {
+		return is.Alternative.End()
+	}
+	if is.Consequence != nil {
+		return is.Consequence.End()
+	}
+	return is.Token.End
+}
+
 func (is *IfStatement) String() string {
 	var out bytes.Buffer
 
@@ -301,11 +616,208 @@ func (is *IfStatement) String() string {
 	return out.String()
 }
 
+// ForStatement is a AST node representing for-in loop, e.g. for (x in [1, 2, 3]) { print(x); }
+type ForStatement struct {
+	Token    token.Token
+	Variable *Identifier // bound to the next element in every iteration
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode() {}
+
+// TokenLiteral returns the ForStatement's token.
+func (fs *ForStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+// Pos returns position of the "for" keyword.
+func (fs *ForStatement) Pos() token.Position {
+	return fs.Token.Pos
+}
+
+// End returns position after the loop's body.
+func (fs *ForStatement) End() token.Position {
+	if fs.Body != nil {
+		return fs.Body.End()
+	}
+	return fs.Token.End
+}
+
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for")
+	out.WriteString("(" + fs.Variable.String() + " in " + fs.Iterable.String() + ") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+// BranchStatement is a AST node representing "break" or "continue" token.
+type BranchStatement struct {
+	Token     token.Token
+	Semicolon token.Position // position of the ";"
+}
+
+func (bs *BranchStatement) statementNode() {}
+
+// TokenLiteral returns the BranchStatement's token.
+func (bs *BranchStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// Pos returns position of the keyword.
+func (bs *BranchStatement) Pos() token.Position {
+	return bs.Token.Pos
+}
+
+// End returns position after the BranchStatement.
+func (bs *BranchStatement) End() token.Position {
+	return statementEnd(bs.Semicolon, nil, bs.Token)
+}
+
+func (bs *BranchStatement) String() string {
+	return bs.TokenLiteral() + ";"
+}
+
+// IfExpression is a AST node representing if expression, e.g. if (a < b) { a } else { b }.
+// It evaluates to the value of the last statement of the branch taken.
+// In the "else if" chain the Alternative holds only the nested IfExpression.
+type IfExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ie *IfExpression) expressionNode() {}
+
+// TokenLiteral returns the IfExpression's token.
+func (ie *IfExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+// Pos returns position of the "if" keyword.
+func (ie *IfExpression) Pos() token.Position {
+	return ie.Token.Pos
+}
+
+// End returns position after the last block of the IfExpression.
+func (ie *IfExpression) End() token.Position {
+	if ie.Alternative != nil {
+		return ie.Alternative.End()
+	}
+	if ie.Consequence != nil {
+		return ie.Consequence.End()
+	}
+	return ie.Token.End
+}
+
+func (ie *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if")
+	out.WriteString(ie.Condition.String() + " ")
+	out.WriteString(ie.Consequence.String())
+
+	if ie.Alternative != nil {
+		out.WriteString("else ")
+		out.WriteString(ie.Alternative.String())
+	}
+
+	return out.String()
+}
+
+// MatchExpression is a AST node representing match expression, e.g. match (x) { 0 => "zero", _ => "other" }.
+// It evaluates to the body of the first case which pattern matches the value.
+type MatchExpression struct {
+	Token  token.Token // "match"
+	Value  Expression
+	Cases  []*MatchCase
+	Rbrace token.Position // position of the closing "}"
+}
+
+func (me *MatchExpression) expressionNode() {}
+
+// TokenLiteral returns the MatchExpression's token.
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+// Pos returns position of the "match" keyword.
+func (me *MatchExpression) Pos() token.Position {
+	return me.Token.Pos
+}
+
+// End returns position after the closing "}".
+func (me *MatchExpression) End() token.Position {
+	return closingEnd(me.Rbrace, me.Token)
+}
+
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	cases := []string{}
+	for _, c := range me.Cases {
+		cases = append(cases, c.String())
+	}
+
+	out.WriteString("match(")
+	out.WriteString(me.Value.String())
+	out.WriteString(") {")
+	out.WriteString(strings.Join(cases, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// MatchCase is a single case of the match expression, e.g. [x, ...rest] if x > 0 => x.
+type MatchCase struct {
+	Pattern Pattern
+	Guard   Expression // has to be true for the case to match, may be nil
+	Body    Expression
+}
+
+// TokenLiteral returns the token of the MatchCase's pattern.
+func (mc *MatchCase) TokenLiteral() string {
+	return mc.Pattern.TokenLiteral()
+}
+
+// Pos returns position of the MatchCase's pattern.
+func (mc *MatchCase) Pos() token.Position {
+	return mc.Pattern.Pos()
+}
+
+// End returns position after the MatchCase's body.
+func (mc *MatchCase) End() token.Position {
+	if mc.Body != nil {
+		return mc.Body.End()
+	}
+	return mc.Pattern.End()
+}
+
+func (mc *MatchCase) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(mc.Pattern.String())
+	if mc.Guard != nil {
+		out.WriteString(" if " + mc.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(mc.Body.String())
+
+	return out.String()
+}
+
 // FunctionLiteral is a AST node representing function literal.
+// The arrow function, e.g. (x) => x * 2, is a function literal which body returns the expression after "=>".
 type FunctionLiteral struct {
-	Token      token.Token
-	Parameters []*Identifier
+	Token      token.Token       // "fun", or the first token of arrow function
+	Parameters []*PatternElement // parameters with a default value follow the ones without it
+	Rest       *Identifier       // bound to the array of arguments not matched by Parameters, may be nil
 	Body       *BlockStatement
+	Arrow      token.Position // position of the "=>" of arrow function, not set for "fun"
 }
 
 func (fl *FunctionLiteral) expressionNode() {}
@@ -315,6 +827,22 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 	return fl.Token.Literal
 }
 
+// Pos returns position of the "fun" keyword or of the arrow function's first token.
+func (fl *FunctionLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
+
+// End returns position after the function's body.
+func (fl *FunctionLiteral) End() token.Position {
+	if fl.Body == nil {
+		return fl.Token.End
+	}
+	if fl.Arrow.IsValid() && len(fl.Body.Statements) > 0 {
+		return fl.Body.Statements[0].End()
+	}
+	return fl.Body.End()
+}
+
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
@@ -323,6 +851,21 @@ func (fl *FunctionLiteral) String() string {
 	for _, p := range fl.Parameters {
 		params = append(params, p.String())
 	}
+	if fl.Rest != nil {
+		params = append(params, "..."+fl.Rest.String())
+	}
+
+	if fl.Arrow.IsValid() {
+		out.WriteString("(")
+		out.WriteString(strings.Join(params, ", "))
+		out.WriteString(") => ")
+		if len(fl.Body.Statements) > 0 {
+			if rs, ok := fl.Body.Statements[0].(*ReturnStatement); ok && rs.ReturnValue != nil {
+				out.WriteString(rs.ReturnValue.String())
+			}
+		}
+		return out.String()
+	}
 
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
@@ -338,6 +881,7 @@ type CallExpression struct {
 	Token     token.Token // LPAREN token
 	Function  Expression  // Identifier or FunctionLiteral
 	Arguments []Expression
+	Rparen    token.Position // position of the closing ")"
 }
 
 func (ce *CallExpression) expressionNode() {}
@@ -347,6 +891,19 @@ func (ce *CallExpression) TokenLiteral() string {
 	return ce.Token.Literal
 }
 
+// Pos returns position of the called function expression.
+func (ce *CallExpression) Pos() token.Position {
+	if ce.Function != nil {
+		return ce.Function.Pos()
+	}
+	return ce.Token.Pos
+}
+
+// End returns position after the closing ")".
+func (ce *CallExpression) End() token.Position {
+	return closingEnd(ce.Rparen, ce.Token)
+}
+
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 
@@ -367,6 +924,7 @@ func (ce *CallExpression) String() string {
 type ArrayLiteral struct {
 	token.Token // "["
 	Elements    []Expression
+	Rbracket    token.Position // position of the closing "]"
 }
 
 func (al *ArrayLiteral) expressionNode() {}
@@ -376,6 +934,16 @@ func (al *ArrayLiteral) TokenLiteral() string {
 	return al.Token.Literal
 }
 
+// Pos returns position of the opening "[".
+func (al *ArrayLiteral) Pos() token.Position {
+	return al.Token.Pos
+}
+
+// End returns position after the closing "]".
+func (al *ArrayLiteral) End() token.Position {
+	return closingEnd(al.Rbracket, al.Token)
+}
+
 func (al *ArrayLiteral) String() string {
 	var out bytes.Buffer
 
@@ -391,11 +959,44 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// SpreadElement is a "..." expression expanded in place of itself
+// in array literals, hash literals and call arguments.
+type SpreadElement struct {
+	Token token.Token // "..."
+	Value Expression
+}
+
+func (se *SpreadElement) expressionNode() {}
+
+// TokenLiteral returns the SpreadElement's "..." token.
+func (se *SpreadElement) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+// Pos returns position of the "...".
+func (se *SpreadElement) Pos() token.Position {
+	return se.Token.Pos
+}
+
+// End returns position after the spread expression.
+func (se *SpreadElement) End() token.Position {
+	if se.Value != nil {
+		return se.Value.End()
+	}
+	return se.Token.End
+}
+
+func (se *SpreadElement) String() string {
+	return se.Token.Literal + se.Value.String()
+}
+
 // IndexExpression expression for gettting elements from array
+// or a field of a hash, e.g. "obj.name" is parsed with the "." token and the "name" string as the index.
 type IndexExpression struct {
-	Token token.Token // "["
-	Left  Expression
-	Right Expression
+	Token    token.Token // "[" or "."
+	Left     Expression
+	Right    Expression
+	Rbracket token.Position // position of the closing "]"
 }
 
 func (ie *IndexExpression) expressionNode() {}
@@ -405,9 +1006,34 @@ func (ie *IndexExpression) TokenLiteral() string {
 	return ie.Token.Literal
 }
 
+// Pos returns position of the indexed expression.
+func (ie *IndexExpression) Pos() token.Position {
+	if ie.Left != nil {
+		return ie.Left.Pos()
+	}
+	return ie.Token.Pos
+}
+
+// End returns position after the closing "]" or after the field name.
+func (ie *IndexExpression) End() token.Position {
+	if ie.Token.Type == token.DOT && ie.Right != nil {
+		return ie.Right.End()
+	}
+	return closingEnd(ie.Rbracket, ie.Token)
+}
+
 func (ie *IndexExpression) String() string {
 	var out bytes.Buffer
 
+	if ie.Token.Type == token.DOT {
+		out.WriteString("(")
+		out.WriteString(ie.Left.String())
+		out.WriteString(".")
+		out.WriteString(ie.Right.String())
+		out.WriteString(")")
+		return out.String()
+	}
+
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
 	out.WriteString("[")
@@ -421,6 +1047,8 @@ func (ie *IndexExpression) String() string {
 type HashLiteral struct {
 	token.Token // "{"
 	Pairs       map[Expression]Expression
+	Keys        []Expression   // keys of the pairs and spread elements in the source order
+	Rbrace      token.Position // position of the closing "}"
 }
 
 func (hl *HashLiteral) expressionNode() {}
@@ -430,12 +1058,26 @@ func (hl *HashLiteral) TokenLiteral() string {
 	return hl.Token.Literal
 }
 
+// Pos returns position of the opening "{".
+func (hl *HashLiteral) Pos() token.Position {
+	return hl.Token.Pos
+}
+
+// End returns position after the closing "}".
+func (hl *HashLiteral) End() token.Position {
+	return closingEnd(hl.Rbrace, hl.Token)
+}
+
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, val := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+val.String())
+	for _, key := range hl.Keys {
+		if spread, ok := key.(*SpreadElement); ok {
+			pairs = append(pairs, spread.String())
+			continue
+		}
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
 	}
 
 	out.WriteString("{")
@@ -443,3 +1085,282 @@ func (hl *HashLiteral) String() string {
 	out.WriteString("}")
 	return out.String()
 }
+
+// Comment is a AST node representing a single "//" or "/* */" comment.
+type Comment struct {
+	Token token.Token
+}
+
+// TokenLiteral returns the Comment's token.
+func (c *Comment) TokenLiteral() string {
+	return c.Token.Literal
+}
+
+// Pos returns position of the comment's opening "/".
+func (c *Comment) Pos() token.Position {
+	return c.Token.Pos
+}
+
+// End returns position after the comment.
+func (c *Comment) End() token.Position {
+	return c.Token.End
+}
+
+func (c *Comment) String() string {
+	return c.Token.Literal
+}
+
+// IsDoc reports whether the comment is a "/** */" doc comment.
+func (c *Comment) IsDoc() bool {
+	return strings.HasPrefix(c.Token.Literal, "/**") && c.Token.Literal != "/**/"
+}
+
+// Text returns the comment's text without the comment markers.
+// In multi line comments the leading "*" of every line is removed as well.
+func (c *Comment) Text() string {
+	text := c.Token.Literal
+	if strings.HasPrefix(text, "//") {
+		return strings.TrimSpace(text[2:])
+	}
+
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	if c.IsDoc() {
+		text = text[1:]
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "*") {
+			line = strings.TrimSpace(line[1:])
+		}
+		lines[i] = line
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// CommentGroup is a AST node representing a sequence of comments
+// with no other tokens and no empty lines between them.
+type CommentGroup struct {
+	List []*Comment
+}
+
+// TokenLiteral returns the first comment's token.
+func (g *CommentGroup) TokenLiteral() string {
+	return g.List[0].TokenLiteral()
+}
+
+// Pos returns position of the first comment.
+func (g *CommentGroup) Pos() token.Position {
+	return g.List[0].Pos()
+}
+
+// End returns position after the last comment.
+func (g *CommentGroup) End() token.Position {
+	return g.List[len(g.List)-1].End()
+}
+
+func (g *CommentGroup) String() string {
+	comments := []string{}
+	for _, c := range g.List {
+		comments = append(comments, c.String())
+	}
+
+	return strings.Join(comments, "\n")
+}
+
+// Text returns text of the comments in the group without the comment markers.
+func (g *CommentGroup) Text() string {
+	lines := []string{}
+	for _, c := range g.List {
+		lines = append(lines, c.Text())
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// CommentMap maps AST nodes to the comment groups attached to them.
+// Groups placed before the node come first, followed by the group placed right after it on the same line.
+type CommentMap map[Node][]*CommentGroup
+
+// Returns position after the statement's semicolon,
+// falling back to the end of its expression or its first token when the semicolon is missing.
+func statementEnd(semicolon token.Position, exp Expression, tok token.Token) token.Position {
+	if semicolon.IsValid() {
+		semicolon.Offset++
+		semicolon.Column++
+		return semicolon
+	}
+	return expressionEnd(exp, tok)
+}
+
+// Returns position after the expression or after the token if the expression is missing.
+func expressionEnd(exp Expression, tok token.Token) token.Position {
+	if exp != nil {
+		return exp.End()
+	}
+	return tok.End
+}
+
+// PatternElement is a pattern with an optional default value, e.g. b = 2 in [a, b = 2].
+type PatternElement struct {
+	Target  Pattern
+	Default Expression // used when the destructured value is missing, may be nil
+}
+
+// TokenLiteral returns the token of the PatternElement's target.
+func (pe *PatternElement) TokenLiteral() string {
+	return pe.Target.TokenLiteral()
+}
+
+// Pos returns position of the PatternElement's target.
+func (pe *PatternElement) Pos() token.Position {
+	return pe.Target.Pos()
+}
+
+// End returns position after the default value or after the target if there is no default.
+func (pe *PatternElement) End() token.Position {
+	if pe.Default != nil {
+		return pe.Default.End()
+	}
+	return pe.Target.End()
+}
+
+func (pe *PatternElement) String() string {
+	if pe.Default != nil {
+		return pe.Target.String() + " = " + pe.Default.String()
+	}
+	return pe.Target.String()
+}
+
+// ArrayPattern is a AST node representing array destructuring pattern, e.g. [a, b, ...rest].
+type ArrayPattern struct {
+	Token    token.Token // "["
+	Elements []*PatternElement
+	Rest     *Identifier    // bound to the array of remaining elements, may be nil
+	Rbracket token.Position // position of the closing "]"
+}
+
+func (ap *ArrayPattern) patternNode() {}
+
+// TokenLiteral returns the ArrayPattern's token.
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+// Pos returns position of the opening "[".
+func (ap *ArrayPattern) Pos() token.Position {
+	return ap.Token.Pos
+}
+
+// End returns position after the closing "]".
+func (ap *ArrayPattern) End() token.Position {
+	return closingEnd(ap.Rbracket, ap.Token)
+}
+
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// PatternProperty binds the value of a hash key, e.g. name: n = "anonymous" in {name: n = "anonymous"}.
+// In the shorthand form {name} the Target is the identifier of the key.
+type PatternProperty struct {
+	Key     Expression // *Identifier standing for a string key, *StringLiteral or *IntegerLiteral
+	Element *PatternElement
+}
+
+// HashPattern is a AST node representing hash destructuring pattern, e.g. {name, age}.
+type HashPattern struct {
+	Token      token.Token // "{"
+	Properties []*PatternProperty
+	Rbrace     token.Position // position of the closing "}"
+}
+
+func (hp *HashPattern) patternNode() {}
+
+// TokenLiteral returns the HashPattern's token.
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+
+// Pos returns position of the opening "{".
+func (hp *HashPattern) Pos() token.Position {
+	return hp.Token.Pos
+}
+
+// End returns position after the closing "}".
+func (hp *HashPattern) End() token.Position {
+	return closingEnd(hp.Rbrace, hp.Token)
+}
+
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	properties := []string{}
+	for _, prop := range hp.Properties {
+		key, keyOk := prop.Key.(*Identifier)
+		target, targetOk := prop.Element.Target.(*Identifier)
+		if keyOk && targetOk && key.Value == target.Value {
+			properties = append(properties, prop.Element.String())
+			continue
+		}
+		properties = append(properties, prop.Key.String()+": "+prop.Element.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(properties, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// LiteralPattern is a pattern of a match case matching only the value equal to the literal, e.g. 0 in [0, x].
+type LiteralPattern struct {
+	Value Expression // IntegerLiteral, FloatLiteral, StringLiteral, BooleanLiteral or negated number literal
+}
+
+func (lp *LiteralPattern) patternNode() {}
+
+// TokenLiteral returns the token of the pattern's literal.
+func (lp *LiteralPattern) TokenLiteral() string {
+	return lp.Value.TokenLiteral()
+}
+
+// Pos returns position of the pattern's literal.
+func (lp *LiteralPattern) Pos() token.Position {
+	return lp.Value.Pos()
+}
+
+// End returns position after the pattern's literal.
+func (lp *LiteralPattern) End() token.Position {
+	return lp.Value.End()
+}
+
+func (lp *LiteralPattern) String() string {
+	return lp.Value.String()
+}
+
+// Returns position after the closing delimiter or after the opening token if the delimiter is missing.
+func closingEnd(closing token.Position, tok token.Token) token.Position {
+	if closing.IsValid() {
+		closing.Offset++
+		closing.Column++
+		return closing
+	}
+	return tok.End
+}
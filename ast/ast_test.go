@@ -27,3 +27,29 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestCommentText(t *testing.T) {
+	tests := []struct {
+		input    string
+		isDoc    bool
+		expected string
+	}{
+		{"// a line comment", false, "a line comment"},
+		{"/* a block comment */", false, "a block comment"},
+		{"/**/", false, ""},
+		{"/** a doc comment */", true, "a doc comment"},
+		{"/**\n * Adds two numbers.\n *\n * Returns the sum.\n */", true, "Adds two numbers.\n\nReturns the sum."},
+		{"/*\n  no stars\n  here\n*/", false, "no stars\nhere"},
+	}
+
+	for i, tt := range tests {
+		comment := &Comment{Token: token.Token{Type: token.COMMENT, Literal: tt.input}}
+
+		if comment.IsDoc() != tt.isDoc {
+			t.Errorf("tests[%d] - IsDoc() wrong. expected=%t, got=%t", i, tt.isDoc, comment.IsDoc())
+		}
+		if comment.Text() != tt.expected {
+			t.Errorf("tests[%d] - Text() wrong. expected=%q, got=%q", i, tt.expected, comment.Text())
+		}
+	}
+}
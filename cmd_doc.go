@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/radlinskii/interpreter/doc"
+	"github.com/radlinskii/interpreter/lexer"
+	"github.com/radlinskii/interpreter/parser"
+)
+
+const docUsage = `Usage: junior doc [-html] <file>
+
+Prints the reference page of the functions defined in the program,
+"-" reads the program from the standard input.
+`
+
+// runDoc implements the "doc" command, it returns the exit code of the program.
+func runDoc(args []string) int {
+	flags := flag.NewFlagSet("doc", flag.ContinueOnError)
+	asHTML := flags.Bool("html", false, "print the page as a HTML document instead of Markdown")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, docUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	defer input.Close()
+
+	l := newLexer(flags.Arg(0), input)
+	l.Mode = lexer.ScanComments
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		reportErrors(flags.Arg(0), p.Errors())
+		return 1
+	}
+
+	page := doc.New(filepath.Base(flags.Arg(0)), program)
+	if *asHTML {
+		err = page.HTML(os.Stdout)
+	} else {
+		err = page.Markdown(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,206 @@
+package diagnostic
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/radlinskii/interpreter/token"
+)
+
+// Severity tells how serious the reported problem is.
+type Severity int
+
+const (
+	// Error prevents the program from being evaluated, or stops its evaluation.
+	Error Severity = iota
+	// Warning reports a likely mistake which doesn't stop the interpreter.
+	Warning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	}
+	return "unknown"
+}
+
+// Code identifies the kind of the problem.
+// Lexical errors start with "L", syntax errors with "P" and evaluation errors with "E".
+type Code string
+
+const (
+	// IllegalCharacter - character not belonging to the language.
+	IllegalCharacter Code = "L001"
+	// InvalidEncoding - input is not valid UTF-8.
+	InvalidEncoding Code = "L002"
+	// UnterminatedString - string literal without the closing quote.
+	UnterminatedString Code = "L003"
+	// UnterminatedComment - multi line comment without the closing "*/".
+	UnterminatedComment Code = "L004"
+	// InvalidEscape - unknown or malformed escape sequence in a string literal.
+	InvalidEscape Code = "L005"
+	// MalformedNumber - number literal that doesn't follow any of the number forms.
+	MalformedNumber Code = "L006"
+	// ReadError - the input could not be read.
+	ReadError Code = "L007"
+
+	// UnexpectedToken - token not allowed by the grammar in its place.
+	UnexpectedToken Code = "P001"
+	// MissingSemicolon - statement not terminated with ";".
+	MissingSemicolon Code = "P002"
+	// InvalidNumber - number literal whose value can't be represented.
+	InvalidNumber Code = "P003"
+	// BuiltinOverride - built-in function's name used as a constant or a parameter.
+	BuiltinOverride Code = "P004"
+	// ConstantAssignment - assignment to a constant, a function's parameter or a loop's variable declared before it.
+	ConstantAssignment Code = "P005"
+	// InvalidBranch - break or continue statement outside of a loop.
+	InvalidBranch Code = "P006"
+	// InvalidParameter - parameter without a default value following a parameter with a default value.
+	InvalidParameter Code = "P007"
+
+	// TypeMismatch - operation not supported by the types of its operands.
+	TypeMismatch Code = "E001"
+	// UnknownIdentifier - identifier not defined in the scope.
+	UnknownIdentifier Code = "E002"
+	// DivisionByZero - division by zero.
+	DivisionByZero Code = "E003"
+	// IndexOutOfRange - index or hash key not present in the collection.
+	IndexOutOfRange Code = "E004"
+	// WrongArgumentCount - function called with wrong number of arguments.
+	WrongArgumentCount Code = "E005"
+	// InvalidReturn - missing return statement or a return in a wrong place.
+	InvalidReturn Code = "E006"
+	// Redeclaration - constant declared twice in one block.
+	Redeclaration Code = "E007"
+	// InvalidOperand - operand value not supported by the operation, e.g. a negative shift count.
+	InvalidOperand Code = "E008"
+	// ConstantReassignment - assignment to a constant, a function's parameter or a loop's variable,
+	// found during evaluation when the name is declared after the assignment, e.g. in a function called later.
+	ConstantReassignment Code = "E009"
+	// NoMatch - value of the match expression not matched by any of its cases.
+	NoMatch Code = "E010"
+)
+
+// Diagnostic describes a problem found in the program.
+type Diagnostic struct {
+	Severity Severity
+	Code     Code
+	Message  string
+	Pos      token.Position // position of the first character of the problem
+	End      token.Position // position after the problem, may be not set
+	Hints    []string       // suggestions how to fix the problem
+}
+
+// Error returns the message followed by the position of the problem.
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s at %s", d.Message, d.Pos)
+}
+
+// Render writes the diagnostics in a human readable form.
+// Every diagnostic is followed by its source line with a caret under the problem,
+// the lines are read from source, which may be nil if the source isn't available.
+func Render(w io.Writer, source io.Reader, diagnostics []Diagnostic) error {
+	lines := sourceLines(source, diagnostics)
+
+	var out bytes.Buffer
+	for _, d := range diagnostics {
+		writeDiagnostic(&out, d, lines)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// Reads only the source lines the diagnostics point to.
+func sourceLines(source io.Reader, diagnostics []Diagnostic) map[int]string {
+	lines := map[int]string{}
+	if source == nil {
+		return lines
+	}
+
+	needed := map[int]bool{}
+	last := 0
+	for _, d := range diagnostics {
+		needed[d.Pos.Line] = true
+		if d.Pos.Line > last {
+			last = d.Pos.Line
+		}
+	}
+
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; line <= last && scanner.Scan(); line++ {
+		if needed[line] {
+			lines[line] = scanner.Text()
+		}
+	}
+
+	return lines
+}
+
+func writeDiagnostic(out *bytes.Buffer, d Diagnostic, lines map[int]string) {
+	out.WriteString(fmt.Sprintf("%s[%s]: %s\n", d.Severity, d.Code, d.Message))
+
+	location := fmt.Sprintf("%d:%d", d.Pos.Line, d.Pos.Column)
+	if d.Pos.Filename != "" {
+		location = d.Pos.Filename + ":" + location
+	}
+
+	gutter := strings.Repeat(" ", len(strconv.Itoa(d.Pos.Line)))
+	out.WriteString(fmt.Sprintf("%s--> %s\n", gutter, location))
+
+	if line, ok := lines[d.Pos.Line]; ok {
+		out.WriteString(fmt.Sprintf("%s |\n", gutter))
+		out.WriteString(fmt.Sprintf("%d | %s\n", d.Pos.Line, line))
+		out.WriteString(fmt.Sprintf("%s | %s\n", gutter, caret(line, d)))
+	}
+
+	for _, hint := range d.Hints {
+		out.WriteString(fmt.Sprintf("%s = hint: %s\n", gutter, hint))
+	}
+	out.WriteString("\n")
+}
+
+// Returns the line marking the problem's span in the source line.
+// Tabs are kept, so the caret lines up with the source however tabs are displayed.
+func caret(line string, d Diagnostic) string {
+	var out bytes.Buffer
+
+	column := 1
+	for _, ch := range line {
+		if column >= d.Pos.Column {
+			break
+		}
+		if ch == '\t' {
+			out.WriteRune('\t')
+		} else {
+			out.WriteRune(' ')
+		}
+		column++
+	}
+
+	width := 1
+	rest := utf8.RuneCountInString(line) - d.Pos.Column + 1
+	switch {
+	case d.End.Line > d.Pos.Line && rest > 0:
+		// the span continues in the following lines, it's marked up to the end of its first line
+		width = rest
+	case d.End.Line == d.Pos.Line && d.End.Column > d.Pos.Column:
+		width = d.End.Column - d.Pos.Column
+	}
+	if width > rest && rest > 0 {
+		width = rest
+	}
+	out.WriteString(strings.Repeat("^", width))
+
+	return out.String()
+}
@@ -0,0 +1,101 @@
+package diagnostic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/radlinskii/interpreter/token"
+)
+
+func TestRender(t *testing.T) {
+	source := "const a = 1;\n\tconst b = a + \"x\";\nconst c = 2"
+
+	diagnostics := []Diagnostic{
+		{
+			Severity: Error,
+			Code:     TypeMismatch,
+			Message:  "type mismatch: INTEGER + STRING",
+			Pos:      token.Position{Filename: "main.jr", Line: 2, Column: 12},
+			End:      token.Position{Filename: "main.jr", Line: 2, Column: 19},
+		},
+		{
+			Severity: Error,
+			Code:     MissingSemicolon,
+			Message:  "expected semicolon",
+			Pos:      token.Position{Filename: "main.jr", Line: 3, Column: 12},
+			End:      token.Position{Filename: "main.jr", Line: 3, Column: 12},
+			Hints:    []string{`add ";" at the end of the statement`},
+		},
+		{
+			Severity: Error,
+			Code:     UnterminatedComment,
+			Message:  "comment not terminated",
+			Pos:      token.Position{Filename: "main.jr", Line: 1, Column: 11},
+			End:      token.Position{Filename: "main.jr", Line: 3, Column: 12},
+		},
+		{
+			Severity: Warning,
+			Code:     UnterminatedComment,
+			Message:  "problem outside of the source",
+			Pos:      token.Position{Line: 10, Column: 1},
+		},
+	}
+
+	expected := "error[E001]: type mismatch: INTEGER + STRING\n" +
+		" --> main.jr:2:12\n" +
+		"  |\n" +
+		"2 | \tconst b = a + \"x\";\n" +
+		"  | \t          ^^^^^^^\n" +
+		"\n" +
+		"error[P002]: expected semicolon\n" +
+		" --> main.jr:3:12\n" +
+		"  |\n" +
+		"3 | const c = 2\n" +
+		"  |            ^\n" +
+		"  = hint: add \";\" at the end of the statement\n" +
+		"\n" +
+		"error[L004]: comment not terminated\n" +
+		" --> main.jr:1:11\n" +
+		"  |\n" +
+		"1 | const a = 1;\n" +
+		"  |           ^^\n" +
+		"\n" +
+		"warning[L004]: problem outside of the source\n" +
+		"  --> 10:1\n" +
+		"\n"
+
+	var out bytes.Buffer
+	if err := Render(&out, strings.NewReader(source), diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("rendered diagnostics wrong.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderWithoutSource(t *testing.T) {
+	d := Diagnostic{
+		Severity: Error,
+		Code:     IllegalCharacter,
+		Message:  `illegal character: "$"`,
+		Pos:      token.Position{Line: 1, Column: 3},
+	}
+
+	expected := "error[L001]: illegal character: \"$\"\n" +
+		" --> 1:3\n" +
+		"\n"
+
+	var out bytes.Buffer
+	if err := Render(&out, nil, []Diagnostic{d}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("rendered diagnostic wrong.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if d.Error() != `illegal character: "$" at line: 1, column: 3` {
+		t.Errorf("d.Error() wrong. got=%q", d.Error())
+	}
+}
@@ -0,0 +1,166 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/radlinskii/interpreter/ast"
+	"github.com/radlinskii/interpreter/token"
+)
+
+// Function describes a function defined with the const statement.
+type Function struct {
+	Name       string
+	Parameters []string
+	Doc        string // text of the "/** */" doc comment, empty if the function isn't documented
+	Pos        token.Position
+}
+
+// Signature returns the function's name followed by its parameters, e.g. "add(x, y)".
+func (f *Function) Signature() string {
+	return f.Name + "(" + strings.Join(f.Parameters, ", ") + ")"
+}
+
+// Page is the reference page listing the functions defined in a program.
+type Page struct {
+	Title     string
+	Functions []*Function
+}
+
+// New creates the reference page of the functions defined at the top level of the program.
+// Doc comments are available only if the program was parsed with the lexer scanning comments.
+func New(title string, program *ast.Program) *Page {
+	page := &Page{Title: title}
+
+	for _, stmnt := range program.Statements {
+		constStmnt, ok := stmnt.(*ast.ConstStatement)
+		if !ok || constStmnt.Name == nil {
+			continue
+		}
+		fn, ok := constStmnt.Value.(*ast.FunctionLiteral)
+		if !ok {
+			continue
+		}
+
+		f := &Function{Name: constStmnt.Name.Value, Parameters: []string{}, Pos: constStmnt.Pos()}
+		for _, param := range fn.Parameters {
+			f.Parameters = append(f.Parameters, param.String())
+		}
+		if fn.Rest != nil {
+			f.Parameters = append(f.Parameters, "..."+fn.Rest.String())
+		}
+		if constStmnt.Doc != nil {
+			f.Doc = constStmnt.Doc.Text()
+		}
+
+		page.Functions = append(page.Functions, f)
+	}
+
+	return page
+}
+
+// references to other functions in the doc text, e.g. [add]
+var linkPattern = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// Replaces references to the functions on the page with links created by link.
+func (p *Page) crossLink(text string, link func(name string) string) string {
+	return linkPattern.ReplaceAllStringFunc(text, func(ref string) string {
+		name := ref[1 : len(ref)-1]
+		if p.function(name) == nil {
+			return ref
+		}
+		return link(name)
+	})
+}
+
+func (p *Page) function(name string) *Function {
+	for _, f := range p.Functions {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Returns the source location of the function, e.g. "lib.jr:12", and the link pointing to its line.
+func sourceLocation(pos token.Position) (string, string) {
+	if pos.Filename == "" {
+		return fmt.Sprintf("line %d", pos.Line), fmt.Sprintf("#L%d", pos.Line)
+	}
+	return fmt.Sprintf("%s:%d", pos.Filename, pos.Line), fmt.Sprintf("%s#L%d", pos.Filename, pos.Line)
+}
+
+// Markdown writes the page in the Markdown format.
+func (p *Page) Markdown(w io.Writer) error {
+	var out bytes.Buffer
+
+	out.WriteString("# " + p.Title + "\n\n")
+
+	out.WriteString("## Index\n\n")
+	for _, f := range p.Functions {
+		out.WriteString(fmt.Sprintf("- [%s](#%s)\n", f.Signature(), f.Name))
+	}
+
+	out.WriteString("\n## Functions\n")
+	for _, f := range p.Functions {
+		out.WriteString(fmt.Sprintf("\n<a id=\"%s\"></a>\n### %s\n\n", f.Name, f.Name))
+		out.WriteString("```\n" + f.Signature() + "\n```\n\n")
+
+		if f.Doc != "" {
+			out.WriteString(p.crossLink(f.Doc, func(name string) string {
+				return fmt.Sprintf("[%s](#%s)", name, name)
+			}))
+			out.WriteString("\n\n")
+		}
+
+		location, link := sourceLocation(f.Pos)
+		out.WriteString(fmt.Sprintf("Source: [%s](%s)\n", location, link))
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// HTML writes the page as a HTML document.
+func (p *Page) HTML(w io.Writer) error {
+	var out bytes.Buffer
+
+	title := html.EscapeString(p.Title)
+	out.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	out.WriteString("<title>" + title + "</title>\n</head>\n<body>\n")
+	out.WriteString("<h1>" + title + "</h1>\n")
+
+	out.WriteString("<h2>Index</h2>\n<ul>\n")
+	for _, f := range p.Functions {
+		out.WriteString(fmt.Sprintf("<li><a href=\"#%s\">%s</a></li>\n", html.EscapeString(f.Name), html.EscapeString(f.Signature())))
+	}
+	out.WriteString("</ul>\n")
+
+	out.WriteString("<h2>Functions</h2>\n")
+	for _, f := range p.Functions {
+		name := html.EscapeString(f.Name)
+		out.WriteString(fmt.Sprintf("<h3 id=\"%s\">%s</h3>\n", name, name))
+		out.WriteString("<pre>" + html.EscapeString(f.Signature()) + "</pre>\n")
+
+		for _, paragraph := range strings.Split(f.Doc, "\n\n") {
+			if paragraph == "" {
+				continue
+			}
+			out.WriteString("<p>" + p.crossLink(html.EscapeString(paragraph), func(name string) string {
+				return fmt.Sprintf("<a href=\"#%s\">%s</a>", name, name)
+			}) + "</p>\n")
+		}
+
+		location, link := sourceLocation(f.Pos)
+		out.WriteString(fmt.Sprintf("<p>Source: <a href=\"%s\">%s</a></p>\n", html.EscapeString(link), html.EscapeString(location)))
+	}
+
+	out.WriteString("</body>\n</html>\n")
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,117 @@
+package doc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/radlinskii/interpreter/lexer"
+	"github.com/radlinskii/interpreter/parser"
+)
+
+const input = `/**
+ * Adds two numbers.
+ *
+ * See also [sub] and [unknown].
+ */
+const add = fun(x, y) { return x + y; };
+
+const sub = fun(x, y = 1, ...others) { return x - y; };
+
+const [one, other] = [fun() { return 1; }, 2];
+
+/** Not a function. */
+const two = 2;`
+
+func testPage(t *testing.T) *Page {
+	l := lexer.New(input)
+	l.Filename = "math.jr"
+	l.Mode = lexer.ScanComments
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser has %d errors: %q", len(p.Errors()), p.Errors())
+	}
+
+	return New("math.jr", program)
+}
+
+func TestNew(t *testing.T) {
+	page := testPage(t)
+
+	tests := []struct {
+		expectedSignature string
+		expectedDoc       string
+		expectedLine      int
+	}{
+		{"add(x, y)", "Adds two numbers.\n\nSee also [sub] and [unknown].", 6},
+		{"sub(x, y = 1, ...others)", "", 8},
+	}
+
+	if len(page.Functions) != len(tests) {
+		t.Fatalf("page.Functions does not contain %d functions. got=%d", len(tests), len(page.Functions))
+	}
+
+	for i, tt := range tests {
+		f := page.Functions[i]
+		if f.Signature() != tt.expectedSignature {
+			t.Errorf("tests[%d] - signature wrong. expected=%q, got=%q", i, tt.expectedSignature, f.Signature())
+		}
+		if f.Doc != tt.expectedDoc {
+			t.Errorf("tests[%d] - doc wrong. expected=%q, got=%q", i, tt.expectedDoc, f.Doc)
+		}
+		if f.Pos.Line != tt.expectedLine {
+			t.Errorf("tests[%d] - line wrong. expected=%d, got=%d", i, tt.expectedLine, f.Pos.Line)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	expected := "# math.jr\n\n" +
+		"## Index\n\n" +
+		"- [add(x, y)](#add)\n" +
+		"- [sub(x, y = 1, ...others)](#sub)\n\n" +
+		"## Functions\n\n" +
+		"<a id=\"add\"></a>\n### add\n\n" +
+		"```\nadd(x, y)\n```\n\n" +
+		"Adds two numbers.\n\nSee also [sub](#sub) and [unknown].\n\n" +
+		"Source: [math.jr:6](math.jr#L6)\n\n" +
+		"<a id=\"sub\"></a>\n### sub\n\n" +
+		"```\nsub(x, y = 1, ...others)\n```\n\n" +
+		"Source: [math.jr:8](math.jr#L8)\n"
+
+	var out bytes.Buffer
+	if err := testPage(t).Markdown(&out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("markdown wrong.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestHTML(t *testing.T) {
+	expected := "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n" +
+		"<title>math.jr</title>\n</head>\n<body>\n" +
+		"<h1>math.jr</h1>\n" +
+		"<h2>Index</h2>\n<ul>\n" +
+		"<li><a href=\"#add\">add(x, y)</a></li>\n" +
+		"<li><a href=\"#sub\">sub(x, y = 1, ...others)</a></li>\n" +
+		"</ul>\n" +
+		"<h2>Functions</h2>\n" +
+		"<h3 id=\"add\">add</h3>\n<pre>add(x, y)</pre>\n" +
+		"<p>Adds two numbers.</p>\n" +
+		"<p>See also <a href=\"#sub\">sub</a> and [unknown].</p>\n" +
+		"<p>Source: <a href=\"math.jr#L6\">math.jr:6</a></p>\n" +
+		"<h3 id=\"sub\">sub</h3>\n<pre>sub(x, y = 1, ...others)</pre>\n" +
+		"<p>Source: <a href=\"math.jr#L8\">math.jr:8</a></p>\n" +
+		"</body>\n</html>\n"
+
+	var out bytes.Buffer
+	if err := testPage(t).HTML(&out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("html wrong.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
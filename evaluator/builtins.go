@@ -1,6 +1,12 @@
 package evaluator
 
 import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/object"
 )
 
@@ -8,7 +14,7 @@ var builtins = map[string]*object.Builtin{
 	"len": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d want=1", len(args))
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1", len(args))
 			}
 
 			switch arg := args[0].(type) {
@@ -16,21 +22,23 @@ var builtins = map[string]*object.Builtin{
 				return &object.Integer{Value: int64(len(arg.Elements))}
 
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+			case *object.Range:
+				return &object.Integer{Value: int64(arg.Len())}
 			default:
-				return newError("argument to `len` not supported, got %s", args[0].Type())
+				return newError(diagnostic.TypeMismatch, "argument to `len` not supported, got %s", args[0].Type())
 			}
 		},
 	},
 	"first": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d want=1", len(args))
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1", len(args))
 			}
 
 			arr, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `first` not supported, got %s", args[0].Type())
+				return newError(diagnostic.TypeMismatch, "argument to `first` not supported, got %s", args[0].Type())
 			}
 			if len(arr.Elements) > 0 {
 				return arr.Elements[0]
@@ -42,12 +50,12 @@ var builtins = map[string]*object.Builtin{
 	"last": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d want=1", len(args))
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1", len(args))
 			}
 
 			arr, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `last` not supported, got %s", args[0].Type())
+				return newError(diagnostic.TypeMismatch, "argument to `last` not supported, got %s", args[0].Type())
 			}
 			length := len(arr.Elements)
 			if length > 0 {
@@ -60,12 +68,12 @@ var builtins = map[string]*object.Builtin{
 	"rest": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d want=1", len(args))
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1", len(args))
 			}
 
 			arr, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `rest` not supported, got %s", args[0].Type())
+				return newError(diagnostic.TypeMismatch, "argument to `rest` not supported, got %s", args[0].Type())
 			}
 			length := len(arr.Elements)
 			if length > 0 {
@@ -81,12 +89,12 @@ var builtins = map[string]*object.Builtin{
 	"push": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
-				return newError("wrong number of arguments. got=%d want=2", len(args))
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=2", len(args))
 			}
 
 			arr, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("first argument to `push` not supported, got %s", args[0].Type())
+				return newError(diagnostic.TypeMismatch, "first argument to `push` not supported, got %s", args[0].Type())
 			}
 			length := len(arr.Elements)
 			newElements := make([]object.Object, length+1, length+1)
@@ -96,6 +104,34 @@ var builtins = map[string]*object.Builtin{
 			return &object.Array{Elements: newElements}
 		},
 	},
+	"range": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1, 2 or 3", len(args))
+			}
+
+			bounds := []int64{}
+			for _, arg := range args {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newError(diagnostic.TypeMismatch, "argument to `range` not supported, got %s", arg.Type())
+				}
+				bounds = append(bounds, integer.Value)
+			}
+
+			switch len(bounds) {
+			case 1:
+				return &object.Range{Start: 0, End: bounds[0], Step: 1}
+			case 2:
+				return &object.Range{Start: bounds[0], End: bounds[1], Step: 1}
+			}
+
+			if bounds[2] == 0 {
+				return newError(diagnostic.InvalidOperand, "range step cannot be zero")
+			}
+			return &object.Range{Start: bounds[0], End: bounds[1], Step: bounds[2]}
+		},
+	},
 	"print": {
 		Fn: func(args ...object.Object) object.Object {
 			for _, arg := range args {
@@ -106,4 +142,51 @@ var builtins = map[string]*object.Builtin{
 			return NULL
 		},
 	},
+	"int": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1", len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return arg
+			case *object.Float:
+				if math.IsNaN(arg.Value) || arg.Value >= math.MaxInt64 || arg.Value < math.MinInt64 {
+					return newError(diagnostic.TypeMismatch, "cannot convert %s to INTEGER", arg.Inspect())
+				}
+				return &object.Integer{Value: int64(arg.Value)}
+			case *object.String:
+				value, err := strconv.ParseInt(strings.TrimSpace(arg.Value), 10, 64)
+				if err != nil {
+					return newError(diagnostic.TypeMismatch, "cannot convert %q to INTEGER", arg.Value)
+				}
+				return &object.Integer{Value: value}
+			default:
+				return newError(diagnostic.TypeMismatch, "argument to `int` not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	"float": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=1", len(args))
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return &object.Float{Value: float64(arg.Value)}
+			case *object.Float:
+				return arg
+			case *object.String:
+				value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+				if err != nil {
+					return newError(diagnostic.TypeMismatch, "cannot convert %q to FLOAT", arg.Value)
+				}
+				return &object.Float{Value: value}
+			default:
+				return newError(diagnostic.TypeMismatch, "argument to `float` not supported, got %s", args[0].Type())
+			}
+		},
+	},
 }
@@ -3,9 +3,13 @@ package evaluator
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/radlinskii/interpreter/ast"
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/object"
+	"github.com/radlinskii/interpreter/token"
 )
 
 var (
@@ -17,12 +21,28 @@ var (
 	NULL = &object.Null{}
 	// VOID is a single object that all the appeareances of nodes without a value will point to.
 	VOID = &object.Void{}
+	// BREAK is a single object that all the evaluated break statements will point to.
+	BREAK = &object.Break{}
+	// CONTINUE is a single object that all the evaluated continue statements will point to.
+	CONTINUE = &object.Continue{}
 )
 
 var programOutput bytes.Buffer
 
 // eval evaluates the AST
+// Errors are given the span of the innermost node which evaluation failed.
 func eval(node ast.Node, env *object.Environment) object.Object {
+	result := evalNode(node, env)
+
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = node.Pos()
+		err.End = node.End()
+	}
+
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	// Statements
 	case *ast.BlockStatement:
@@ -33,15 +53,30 @@ func eval(node ast.Node, env *object.Environment) object.Object {
 		return evalIfStatement(node, env)
 	case *ast.ReturnStatement:
 		return evalReturnStatement(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.BranchStatement:
+		if node.Token.Type == token.BREAK {
+			return BREAK
+		}
+		return CONTINUE
 	case *ast.ConstStatement:
 		return evalConstStatement(node, env)
+	case *ast.LetStatement:
+		return evalLetStatement(node, env)
+	case *ast.AssignStatement:
+		return evalAssignStatement(node, env)
 	//Expressions
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.BooleanLiteral:
 		return evalBoolToBooleanObjectReference(node.Value)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
 	case *ast.PrefixExpression:
 		right := eval(node.Right, env)
 		if isError(right) {
@@ -49,6 +84,12 @@ func eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
+		if node.Operator == "|>" {
+			return evalPipeExpression(node, env)
+		}
 		left := eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -60,10 +101,14 @@ func eval(node ast.Node, env *object.Environment) object.Object {
 		return evalInfixExpression(node.Operator, left, right)
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
+	case *ast.IfExpression:
+		return evalIfExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{Parameters: params, Rest: node.Rest, Env: env, Body: body}
 	case *ast.CallExpression:
 		fun := eval(node.Function, env)
 		if isError(fun) {
@@ -85,6 +130,9 @@ func eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(left) {
 			return left
 		}
+		if node.Token.Type == token.DOT {
+			return evalFieldExpression(left, node.Right.(*ast.StringLiteral).Value)
+		}
 		right := eval(node.Right, env)
 		if isError(right) {
 			return right
@@ -106,7 +154,10 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 
 		switch result := result.(type) {
 		case *object.Return:
-			return newError("return statement not permitted outside function body")
+			err := newError(diagnostic.InvalidReturn, "return statement not permitted outside function body")
+			err.Pos = stmnt.Pos()
+			err.End = stmnt.End()
+			return err
 		case *object.Error:
 			return result
 		}
@@ -116,15 +167,21 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 }
 
 // EvalProgram starts evaluation of the AST.
-func EvalProgram(program *ast.Program, env *object.Environment) string {
+// It returns output of the program and the errors which stopped its evaluation.
+func EvalProgram(program *ast.Program, env *object.Environment) (string, []diagnostic.Diagnostic) {
 	evaluated := evalProgram(program, env)
 
-	programOutput.WriteString(evaluated.Inspect())
+	var errors []diagnostic.Diagnostic
+	if err, ok := evaluated.(*object.Error); ok {
+		errors = append(errors, err.Diagnostic())
+	} else if evaluated != nil {
+		programOutput.WriteString(evaluated.Inspect())
+	}
 
 	retStr := programOutput.String()
 	programOutput.Reset()
 
-	return retStr
+	return retStr, errors
 }
 
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
@@ -137,7 +194,7 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 
 		if result != nil {
 			rt := result.Type()
-			if rt == object.RETURN || rt == object.ERROR {
+			if rt == object.RETURN || rt == object.ERROR || rt == object.BREAK || rt == object.CONTINUE {
 				return result
 			}
 		}
@@ -152,8 +209,10 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 		return evalBangOperatorExpression(right)
 	case "-":
 		return evalMinusPrefixOperatorExpression(right)
+	case "~":
+		return evalBitNotOperatorExpression(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newError(diagnostic.TypeMismatch, "unknown operator: %s%s", operator, right.Type())
 	}
 }
 
@@ -164,23 +223,36 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 	case FALSE:
 		return TRUE
 	default:
-		return newError("expected BOOLEAN in negation expression, got: %s", right.Type())
+		return newError(diagnostic.TypeMismatch, "expected BOOLEAN in negation expression, got: %s", right.Type())
 	}
 }
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER {
-		return newError("unknown operator: -%s", right.Type())
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newError(diagnostic.TypeMismatch, "unknown operator: -%s", right.Type())
 	}
+}
 
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
+func evalBitNotOperatorExpression(right object.Object) object.Object {
+	integer, ok := right.(*object.Integer)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "unknown operator: ~%s", right.Type())
+	}
+	return &object.Integer{Value: ^integer.Value}
 }
 
+// Integers are promoted to floats when the other operand is a float.
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
+	case isNumber(left) && isNumber(right) && (left.Type() == object.FLOAT || right.Type() == object.FLOAT):
+		return evalFloatInfixExpression(operator, left, right)
 	case left.Type() != right.Type(): // handling type mismatch error first
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newError(diagnostic.TypeMismatch, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	case left.Type() == object.INTEGER:
 		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.STRING:
@@ -190,7 +262,7 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 	case operator == "!=":
 		return evalBoolToBooleanObjectReference(left != right)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newError(diagnostic.TypeMismatch, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -205,7 +277,34 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case "*":
 		return &object.Integer{Value: leftVal * rightVal}
 	case "/":
+		if rightVal == 0 {
+			return newError(diagnostic.DivisionByZero, "division by zero")
+		}
 		return &object.Integer{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newError(diagnostic.DivisionByZero, "modulo by zero")
+		}
+		return &object.Integer{Value: leftVal % rightVal}
+	case "**":
+		if rightVal < 0 {
+			return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
+		}
+		return &object.Integer{Value: integerPower(leftVal, rightVal)}
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<", ">>":
+		if rightVal < 0 {
+			return newError(diagnostic.InvalidOperand, "negative shift count: %d", rightVal)
+		}
+		if operator == "<<" {
+			return &object.Integer{Value: leftVal << uint64(rightVal)}
+		}
+		return &object.Integer{Value: leftVal >> uint64(rightVal)}
 	case "<":
 		return evalBoolToBooleanObjectReference(leftVal < rightVal)
 	case ">":
@@ -219,10 +318,74 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case ">=":
 		return evalBoolToBooleanObjectReference(leftVal >= rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newError(diagnostic.TypeMismatch, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/":
+		if rightVal == 0 {
+			return newError(diagnostic.DivisionByZero, "division by zero")
+		}
+		return &object.Float{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newError(diagnostic.DivisionByZero, "modulo by zero")
+		}
+		return &object.Float{Value: math.Mod(leftVal, rightVal)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftVal, rightVal)}
+	case "<":
+		return evalBoolToBooleanObjectReference(leftVal < rightVal)
+	case ">":
+		return evalBoolToBooleanObjectReference(leftVal > rightVal)
+	case "==":
+		return evalBoolToBooleanObjectReference(leftVal == rightVal)
+	case "!=":
+		return evalBoolToBooleanObjectReference(leftVal != rightVal)
+	case "<=":
+		return evalBoolToBooleanObjectReference(leftVal <= rightVal)
+	case ">=":
+		return evalBoolToBooleanObjectReference(leftVal >= rightVal)
+	default:
+		return newError(diagnostic.TypeMismatch, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// Raises base to the non-negative exponent by repeated squaring.
+func integerPower(base, exponent int64) int64 {
+	result := int64(1)
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result *= base
+		}
+		base *= base
+		exponent >>= 1
+	}
+	return result
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER || obj.Type() == object.FLOAT
+}
+
+// Returns value of the Integer or Float object as float64.
+func toFloat(obj object.Object) float64 {
+	if i, ok := obj.(*object.Integer); ok {
+		return float64(i.Value)
+	}
+	return obj.(*object.Float).Value
+}
+
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
@@ -234,10 +397,41 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 	case "!=":
 		return evalBoolToBooleanObjectReference(leftVal != rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newError(diagnostic.TypeMismatch, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+// Evaluates "&&" and "||" expressions.
+// The right operand is evaluated only if the left one doesn't determine the result.
+func evalLogicalExpression(ie *ast.InfixExpression, env *object.Environment) object.Object {
+	left := eval(ie.Left, env)
+	if isError(left) {
+		return left
+	}
+	leftVal, ok := isTruthy(left)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "expected BOOLEAN as left operand of %s got: %s", ie.Operator, left.Type())
+	}
+
+	if ie.Operator == "&&" && !leftVal {
+		return FALSE
+	}
+	if ie.Operator == "||" && leftVal {
+		return TRUE
+	}
+
+	right := eval(ie.Right, env)
+	if isError(right) {
+		return right
+	}
+	rightVal, ok := isTruthy(right)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "expected BOOLEAN as right operand of %s got: %s", ie.Operator, right.Type())
+	}
+
+	return evalBoolToBooleanObjectReference(rightVal)
+}
+
 func evalBoolToBooleanObjectReference(val bool) object.Object {
 	if val {
 		return TRUE
@@ -253,7 +447,7 @@ func evalIfStatement(ie *ast.IfStatement, env *object.Environment) object.Object
 
 	isConditionTrue, ok := isTruthy(condition)
 	if !ok {
-		return newError("expected BOOLEAN as condition in if-statement got: %s", condition.Type())
+		return newError(diagnostic.TypeMismatch, "expected BOOLEAN as condition in if-statement got: %s", condition.Type())
 	}
 
 	if isConditionTrue {
@@ -265,6 +459,108 @@ func evalIfStatement(ie *ast.IfStatement, env *object.Environment) object.Object
 	return NULL
 }
 
+func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
+	condition := eval(ie.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	isConditionTrue, ok := isTruthy(condition)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "expected BOOLEAN as condition in if-expression got: %s", condition.Type())
+	}
+
+	branch := ie.Alternative
+	if isConditionTrue {
+		branch = ie.Consequence
+	}
+
+	result := eval(branch, env)
+	switch result.(type) {
+	case nil:
+		return NULL
+	case *object.Return:
+		return newError(diagnostic.InvalidReturn, "return statement not permitted in if-expression")
+	case *object.Error:
+		return result
+	}
+
+	// only an expression ending the branch is its value, e.g. the value of a declared constant isn't
+	if _, ok := branch.Statements[len(branch.Statements)-1].(*ast.ExpressionStatement); !ok {
+		return NULL
+	}
+
+	return result
+}
+
+// Every iteration binds the next element to the loop's variable in a new scope, so the body can't change it.
+func evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var length uint64
+	var element func(i uint64) object.Object
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		length = uint64(len(iterable.Elements))
+		element = func(i uint64) object.Object { return iterable.Elements[i] }
+	case *object.Hash:
+		keys := sortedHashKeys(iterable)
+		length = uint64(len(keys))
+		element = func(i uint64) object.Object { return keys[i] }
+	case *object.Range:
+		length = iterable.Len()
+		element = func(i uint64) object.Object { return &object.Integer{Value: iterable.At(i)} }
+	default:
+		return newError(diagnostic.TypeMismatch, "expected ARRAY, HASH or RANGE in for statement got: %s", iterable.Type())
+	}
+
+	for i := uint64(0); i < length; i++ {
+		loopEnv := object.NewEnclosedEnvironment(env)
+		loopEnv.Set(fs.Variable.Value, element(i))
+
+		result := eval(fs.Body, loopEnv)
+		if result == BREAK {
+			break
+		}
+		if result != nil && (result.Type() == object.RETURN || result.Type() == object.ERROR) {
+			return result
+		}
+	}
+
+	return NULL
+}
+
+// Returns keys of the hash in order: booleans, floats, integers and strings, each of them sorted.
+func sortedHashKeys(hash *object.Hash) []object.Object {
+	keys := []object.Object{}
+	for _, pair := range hash.Pairs {
+		keys = append(keys, pair.Key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type() != keys[j].Type() {
+			return keys[i].Type() < keys[j].Type()
+		}
+		switch key := keys[i].(type) {
+		case *object.Boolean:
+			return !key.Value && keys[j].(*object.Boolean).Value
+		case *object.Float:
+			return key.Value < keys[j].(*object.Float).Value
+		case *object.Integer:
+			return key.Value < keys[j].(*object.Integer).Value
+		case *object.String:
+			return key.Value < keys[j].(*object.String).Value
+		}
+		return false
+	})
+
+	return keys
+}
+
 func isTruthy(obj object.Object) (val, ok bool) {
 	switch obj {
 	case FALSE:
@@ -290,17 +586,19 @@ func evalIdentifier(i *ast.Identifier, env *object.Environment) object.Object {
 		return builtin
 	}
 
-	return newError("unknown identifier: %s", i.Value)
+	return newError(diagnostic.UnknownIdentifier, "unknown identifier: %s", i.Value)
 }
 
 func evalIndexExpression(left, right object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY && right.Type() == object.INTEGER:
 		return evalArrayIndexExpression(left, right)
+	case left.Type() == object.STRING && right.Type() == object.INTEGER:
+		return evalStringIndexExpression(left, right)
 	case left.Type() == object.HASH:
 		return evalHashIndexExpression(left, right)
 	default:
-		return newError("index operator not supported: %s[%s]", left.Type(), right.Type())
+		return newError(diagnostic.TypeMismatch, "index operator not supported: %s[%s]", left.Type(), right.Type())
 	}
 }
 
@@ -310,32 +608,86 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 	max := int64(len(arrayObject.Elements) - 1)
 
 	if i < 0 || i > max {
-		return newError("index out of boundaries")
+		return newError(diagnostic.IndexOutOfRange, "index out of boundaries")
 	}
 
 	return arrayObject.Elements[i]
 }
 
+// Returns the character of the string at given index.
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	chars := []rune(str.(*object.String).Value)
+	i := index.(*object.Integer).Value
+	max := int64(len(chars) - 1)
+
+	if i < 0 || i > max {
+		return newError(diagnostic.IndexOutOfRange, "index out of boundaries")
+	}
+
+	return &object.String{Value: string(chars[i])}
+}
+
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
 
 	key, ok := index.(object.Hashable)
 	if !ok {
-		return newError("index operator not supported: %s[%s]", hash.Type(), index.Type())
+		return newError(diagnostic.TypeMismatch, "index operator not supported: %s[%s]", hash.Type(), index.Type())
 	}
 
 	pair, ok := hashObject.Pairs[key.HashKey()]
 	if !ok {
-		return newError("No hash pair in %q with key %q", hash.Inspect(), index.Inspect())
+		return newError(diagnostic.IndexOutOfRange, "No hash pair in %q with key %q", hash.Inspect(), index.Inspect())
+	}
+
+	return pair.Value
+}
+
+// Returns value of the hash stored under the field's name.
+func evalFieldExpression(left object.Object, field string) object.Object {
+	hash, ok := left.(*object.Hash)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "field access not supported: %s.%s", left.Type(), field)
+	}
+
+	pair, ok := hash.Pairs[(&object.String{Value: field}).HashKey()]
+	if !ok {
+		return newError(diagnostic.IndexOutOfRange, "no field %q in hash", field)
 	}
 
 	return pair.Value
 }
 
+// Joins the string's parts with the Inspect text of the interpolated values.
+func evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out bytes.Buffer
+
+	for _, part := range node.Parts {
+		val := eval(part, env)
+		if isError(val) {
+			return val
+		}
+		out.WriteString(val.Inspect())
+	}
+
+	return &object.String{Value: out.String()}
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
 	pairs := make(map[object.HashKey]object.HashPair)
 
-	for keyNode, valueNode := range node.Pairs {
+	for _, keyNode := range node.Keys {
+		if spread, ok := keyNode.(*ast.SpreadElement); ok {
+			hash, err := evalSpreadElement(spread, object.HASH, env)
+			if err != nil {
+				return err
+			}
+			for hashed, pair := range hash.(*object.Hash).Pairs {
+				pairs[hashed] = pair
+			}
+			continue
+		}
+
 		key := eval(keyNode, env)
 		if isError(key) {
 			return key
@@ -343,10 +695,10 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 
 		hashKey, ok := key.(object.Hashable)
 		if !ok {
-			return newError("%s can't be used as hash key", key.Type())
+			return newError(diagnostic.TypeMismatch, "%s can't be used as hash key", key.Type())
 		}
 
-		value := eval(valueNode, env)
+		value := eval(node.Pairs[keyNode], env)
 		if isError(value) {
 			return value
 		}
@@ -362,6 +714,15 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	var result []object.Object
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadElement); ok {
+			array, err := evalSpreadElement(spread, object.ARRAY, env)
+			if err != nil {
+				return []object.Object{err}
+			}
+			result = append(result, array.(*object.Array).Elements...)
+			continue
+		}
+
 		evaluated := eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -372,6 +733,23 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
+// Evaluates the spread value and checks if it can be expanded in place of the spread element.
+func evalSpreadElement(spread *ast.SpreadElement, expected object.Type, env *object.Environment) (object.Object, *object.Error) {
+	val := eval(spread.Value, env)
+	if err, ok := val.(*object.Error); ok {
+		return nil, err
+	}
+
+	if val.Type() != expected {
+		err := newError(diagnostic.TypeMismatch, "expected %s in spread got: %s", expected, val.Type())
+		err.Pos = spread.Pos()
+		err.End = spread.End()
+		return nil, err
+	}
+
+	return val, nil
+}
+
 func evalReturnStatement(rs *ast.ReturnStatement, env *object.Environment) object.Object {
 	if rs.ReturnValue == nil {
 		return VOID
@@ -384,8 +762,19 @@ func evalReturnStatement(rs *ast.ReturnStatement, env *object.Environment) objec
 }
 
 func evalConstStatement(cs *ast.ConstStatement, env *object.Environment) object.Object {
+	if cs.Pattern != nil {
+		val := eval(cs.Value, env)
+		if isError(val) {
+			return val
+		}
+		if err := bindPattern(cs.Pattern, val, env); err != nil {
+			return err
+		}
+		return val
+	}
+
 	if _, ok := env.ShallowGet(cs.Name.Value); ok {
-		return newError("redeclared constant: %q in one block", cs.Name.Value)
+		return newError(diagnostic.Redeclaration, "redeclared constant: %q in one block", cs.Name.Value)
 	}
 
 	val := eval(cs.Value, env)
@@ -396,10 +785,321 @@ func evalConstStatement(cs *ast.ConstStatement, env *object.Environment) object.
 	return env.Set(cs.Name.Value, val)
 }
 
+// Binds the parts of the value to the constants of the pattern.
+// Errors are given the span of the innermost pattern which didn't match the value.
+func bindPattern(pattern ast.Pattern, val object.Object, env *object.Environment) *object.Error {
+	var err *object.Error
+
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		err = bindIdentifier(pattern, val, env)
+	case *ast.ArrayPattern:
+		err = bindArrayPattern(pattern, val, env)
+	case *ast.HashPattern:
+		err = bindHashPattern(pattern, val, env)
+	}
+
+	if err != nil && !err.Pos.IsValid() {
+		err.Pos = pattern.Pos()
+		err.End = pattern.End()
+	}
+
+	return err
+}
+
+func bindIdentifier(ident *ast.Identifier, val object.Object, env *object.Environment) *object.Error {
+	if _, ok := env.ShallowGet(ident.Value); ok {
+		return newError(diagnostic.Redeclaration, "redeclared constant: %q in one block", ident.Value)
+	}
+	env.Set(ident.Value, val)
+	return nil
+}
+
+func bindArrayPattern(pattern *ast.ArrayPattern, val object.Object, env *object.Environment) *object.Error {
+	array, ok := val.(*object.Array)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "cannot destructure %s with array pattern", val.Type())
+	}
+
+	for i, element := range pattern.Elements {
+		var elementVal object.Object
+		if i < len(array.Elements) {
+			elementVal = array.Elements[i]
+		} else if element.Default == nil {
+			err := newError(diagnostic.IndexOutOfRange, "cannot destructure array of length %d, pattern expects at least %d elements", len(array.Elements), i+1)
+			err.Pos = element.Pos()
+			err.End = element.End()
+			return err
+		}
+		if err := bindPatternElement(element, elementVal, env); err != nil {
+			return err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := []object.Object{}
+		if len(pattern.Elements) < len(array.Elements) {
+			rest = append(rest, array.Elements[len(pattern.Elements):]...)
+		}
+		return bindPattern(pattern.Rest, &object.Array{Elements: rest}, env)
+	}
+
+	return nil
+}
+
+func bindHashPattern(pattern *ast.HashPattern, val object.Object, env *object.Environment) *object.Error {
+	hash, ok := val.(*object.Hash)
+	if !ok {
+		return newError(diagnostic.TypeMismatch, "cannot destructure %s with hash pattern", val.Type())
+	}
+
+	for _, property := range pattern.Properties {
+		var propertyVal object.Object
+		if pair, ok := hash.Pairs[patternKey(property.Key).HashKey()]; ok {
+			propertyVal = pair.Value
+		} else if property.Element.Default == nil {
+			err := newError(diagnostic.IndexOutOfRange, "cannot destructure hash without key: %s", property.Key.String())
+			err.Pos = property.Key.Pos()
+			err.End = property.Element.End()
+			return err
+		}
+		if err := bindPatternElement(property.Element, propertyVal, env); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Evaluates the body of the first case which pattern matches the value and which guard is true.
+// Every case is matched in a new environment holding the identifiers bound by its pattern.
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	val := eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	for _, matchCase := range node.Cases {
+		caseEnv := object.NewEnclosedEnvironment(env)
+
+		matched, err := matchPattern(matchCase.Pattern, val, caseEnv)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if matchCase.Guard != nil {
+			guard := eval(matchCase.Guard, caseEnv)
+			if isError(guard) {
+				return guard
+			}
+			if guard.Type() != object.BOOLEAN {
+				return newError(diagnostic.TypeMismatch, "expected BOOLEAN as guard in match case got: %s", guard.Type())
+			}
+			if guard == FALSE {
+				continue
+			}
+		}
+
+		return eval(matchCase.Body, caseEnv)
+	}
+
+	return newError(diagnostic.NoMatch, "no case of match expression matched value: %s", val.Inspect())
+}
+
+// Checks if the value has the shape of the pattern and binds the matched parts to the pattern's identifiers.
+// The "_" identifier matches any value without binding it.
+func matchPattern(pattern ast.Pattern, val object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value == "_" {
+			return true, nil
+		}
+		return true, bindPattern(pattern, val, env)
+	case *ast.LiteralPattern:
+		literal := eval(pattern.Value, env)
+		if err, ok := literal.(*object.Error); ok {
+			return false, err
+		}
+		return literalEquals(literal, val), nil
+	case *ast.ArrayPattern:
+		array, ok := val.(*object.Array)
+		if !ok || len(array.Elements) < len(pattern.Elements) || pattern.Rest == nil && len(array.Elements) > len(pattern.Elements) {
+			return false, nil
+		}
+		for i, element := range pattern.Elements {
+			if matched, err := matchPattern(element.Target, array.Elements[i], env); !matched || err != nil {
+				return false, err
+			}
+		}
+		if pattern.Rest != nil {
+			rest := append([]object.Object{}, array.Elements[len(pattern.Elements):]...)
+			return matchPattern(pattern.Rest, &object.Array{Elements: rest}, env)
+		}
+		return true, nil
+	case *ast.HashPattern:
+		hash, ok := val.(*object.Hash)
+		if !ok {
+			return false, nil
+		}
+		for _, property := range pattern.Properties {
+			pair, ok := hash.Pairs[patternKey(property.Key).HashKey()]
+			if !ok {
+				return false, nil
+			}
+			if matched, err := matchPattern(property.Element.Target, pair.Value, env); !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Reports whether the value of the literal pattern is equal to the matched value.
+// Numbers are compared as by the "==" operator, so integers are promoted to floats, e.g. 1 matches 1.0.
+func literalEquals(literal, val object.Object) bool {
+	switch literal := literal.(type) {
+	case *object.Integer, *object.Float:
+		if val.Type() != object.INTEGER && val.Type() != object.FLOAT {
+			return false
+		}
+		return evalInfixExpression("==", literal, val) == TRUE
+	case *object.String:
+		other, ok := val.(*object.String)
+		return ok && literal.Value == other.Value
+	case *object.Boolean:
+		return literal == val
+	}
+	return false
+}
+
+// Returns the hash key of the pattern's property, identifiers stand for string keys.
+func patternKey(key ast.Expression) object.Hashable {
+	switch key := key.(type) {
+	case *ast.Identifier:
+		return &object.String{Value: key.Value}
+	case *ast.StringLiteral:
+		return &object.String{Value: key.Value}
+	case *ast.IntegerLiteral:
+		return &object.Integer{Value: key.Value}
+	}
+	return nil
+}
+
+// Binds the value to the element's target, the missing value (nil) is replaced with the element's default.
+// Defaults are evaluated after binding the preceding elements, so they can refer to them.
+func bindPatternElement(element *ast.PatternElement, val object.Object, env *object.Environment) *object.Error {
+	if val == nil {
+		val = eval(element.Default, env)
+		if err, ok := val.(*object.Error); ok {
+			return err
+		}
+	}
+
+	return bindPattern(element.Target, val, env)
+}
+
+func evalLetStatement(ls *ast.LetStatement, env *object.Environment) object.Object {
+	if _, ok := env.ShallowGet(ls.Name.Value); ok {
+		return newError(diagnostic.Redeclaration, "redeclared variable: %q in one block", ls.Name.Value)
+	}
+
+	val := eval(ls.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	return env.SetMutable(ls.Name.Value, val)
+}
+
+func evalAssignStatement(as *ast.AssignStatement, env *object.Environment) object.Object {
+	val := eval(as.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	found, mutable := env.Assign(as.Name.Value, val)
+	if !found {
+		return newError(diagnostic.UnknownIdentifier, "assignment to undeclared variable: %s", as.Name.Value)
+	}
+	if !mutable {
+		return newError(diagnostic.ConstantReassignment, "cannot reassign constant: %q", as.Name.Value)
+	}
+
+	return val
+}
+
+// Calls the function on the right side of "|>" with the left operand as the first argument,
+// e.g. "x |> f(a)" calls "f(x, a)" and "x |> f" calls "f(x)".
+// Every error of the stage says which stage of the pipeline failed. Errors raised by the call itself,
+// e.g. a wrong number of arguments, are given the span of the stage, the other ones keep their span.
+func evalPipeExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	result := evalPipeStage(node.Right, left, env)
+	if err, ok := result.(*object.Error); ok {
+		err.Message = fmt.Sprintf("pipeline stage %d (%s) failed: %s", pipelineStage(node), node.Right.String(), err.Message)
+		if !err.Pos.IsValid() {
+			err.Pos = node.Right.Pos()
+			err.End = node.Right.End()
+		}
+	}
+
+	return result
+}
+
+// Calls the stage's function with the piped value prepended to the stage's arguments.
+func evalPipeStage(stage ast.Expression, piped object.Object, env *object.Environment) object.Object {
+	function := stage
+	call, isCall := stage.(*ast.CallExpression)
+	if isCall {
+		function = call.Function
+	}
+
+	fun := eval(function, env)
+	if isError(fun) {
+		return fun
+	}
+
+	args := []object.Object{piped}
+	if isCall {
+		rest := evalExpressions(call.Arguments, env)
+		if len(rest) == 1 && isError(rest[0]) {
+			return rest[0]
+		}
+		args = append(args, rest...)
+	}
+
+	return applyFunction(fun, args)
+}
+
+// Returns number of the pipeline's stage, the first function the value is piped to is the stage 1.
+func pipelineStage(node *ast.InfixExpression) int {
+	stage := 1
+	for {
+		left, ok := node.Left.(*ast.InfixExpression)
+		if !ok || left.Operator != "|>" {
+			return stage
+		}
+		node = left
+		stage++
+	}
+}
+
 func applyFunction(fun object.Object, args []object.Object) object.Object {
 	switch function := fun.(type) {
 	case *object.Function:
-		extendedEnv := extendedFunctionEnv(function, args)
+		extendedEnv, err := extendedFunctionEnv(function, args)
+		if err != nil {
+			return err
+		}
 		evaluated := evalFunctionBody(function.Body, extendedEnv)
 
 		if isError(evaluated) {
@@ -410,7 +1110,7 @@ func applyFunction(fun object.Object, args []object.Object) object.Object {
 	case *object.Builtin:
 		return function.Fn(args...)
 	default:
-		return newError("not a function: %s", function.Type())
+		return newError(diagnostic.TypeMismatch, "not a function: %s", function.Type())
 	}
 }
 
@@ -428,17 +1128,58 @@ func evalFunctionBody(body *ast.BlockStatement, env *object.Environment) object.
 		}
 	}
 
-	return newError("missing return at the end of function body")
+	return newError(diagnostic.InvalidReturn, "missing return at the end of function body")
 }
 
-func extendedFunctionEnv(fun *object.Function, args []object.Object) *object.Environment {
+// Parameters are bound in order, so the default values can refer to the preceding parameters.
+func extendedFunctionEnv(fun *object.Function, args []object.Object) (*object.Environment, *object.Error) {
+	if err := checkArgumentCount(fun, len(args)); err != nil {
+		return nil, err
+	}
+
 	env := object.NewEnclosedEnvironment(fun.Env)
 
 	for paramIdx, param := range fun.Parameters {
-		env.Set(param.Value, args[paramIdx])
+		var arg object.Object
+		if paramIdx < len(args) {
+			arg = args[paramIdx]
+		}
+		if err := bindPatternElement(param, arg, env); err != nil {
+			return nil, err
+		}
+	}
+
+	if fun.Rest != nil {
+		rest := []object.Object{}
+		if len(fun.Parameters) < len(args) {
+			rest = append(rest, args[len(fun.Parameters):]...)
+		}
+		if err := bindPattern(fun.Rest, &object.Array{Elements: rest}, env); err != nil {
+			return nil, err
+		}
+	}
+
+	return env, nil
+}
+
+func checkArgumentCount(fun *object.Function, count int) *object.Error {
+	required := 0
+	for _, param := range fun.Parameters {
+		if param.Default == nil {
+			required++
+		}
+	}
+
+	switch {
+	case fun.Rest != nil && count < required:
+		return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want at least %d", count, required)
+	case fun.Rest == nil && required == len(fun.Parameters) && count != required:
+		return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=%d", count, required)
+	case fun.Rest == nil && (count < required || count > len(fun.Parameters)):
+		return newError(diagnostic.WrongArgumentCount, "wrong number of arguments. got=%d want=%d to %d", count, required, len(fun.Parameters))
 	}
 
-	return env
+	return nil
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -449,8 +1190,8 @@ func unwrapReturnValue(obj object.Object) object.Object {
 	return obj
 }
 
-func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+func newError(code diagnostic.Code, format string, a ...interface{}) *object.Error {
+	return &object.Error{Code: code, Message: fmt.Sprintf(format, a...)}
 }
 
 func isError(obj object.Object) bool {
@@ -3,6 +3,7 @@ package evaluator
 import (
 	"testing"
 
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/lexer"
 	"github.com/radlinskii/interpreter/object"
 	"github.com/radlinskii/interpreter/parser"
@@ -49,6 +50,7 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"3 * 3 * 3 + 10;", 37},
 		{"3 * (3 * 3) + 10;", 37},
 		{"(5 + 10 * 2 + 15 / 3) * 2 + -10;", 50},
+		{"0xFF + 0b1 + 0o7 + 1_000;", 1263},
 	}
 
 	for _, tt := range tests {
@@ -59,6 +61,158 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.5;", 3.5},
+		{"-2.5;", -2.5},
+		{"1.5 + 1.5;", 3},
+		{"84.0 / 10;", 8.4},
+		{"84 / 10.0;", 8.4},
+		{"2 * 0.25;", 0.5},
+		{"1 - 0.5;", 0.5},
+		{"(1 + 2 + 3) / 3.0;", 2},
+		{"1e3 + 1;", 1001},
+		{"float(84) / 10;", 8.4},
+		{`float("2.5");`, 2.5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		if !testFloatObject(t, evaluated, tt.expected) {
+			return
+		}
+	}
+}
+
+func TestNumberConversions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"84 / 10;", 8},
+		{"int(8.9);", 8},
+		{"int(-8.9);", -8},
+		{"int(42);", 42},
+		{`int(" 42 ");`, 42},
+		{"1 / 0;", "division by zero"},
+		{"1.5 / 0;", "division by zero"},
+		{`int("4.2");`, `cannot convert "4.2" to INTEGER`},
+		{`float("abc");`, `cannot convert "abc" to FLOAT`},
+		{"int(1e300);", "cannot convert 1e+300 to INTEGER"},
+		{"int(true);", "argument to `int` not supported, got BOOLEAN"},
+		{"float(1, 2);", "wrong number of arguments. got=2 want=1"},
+		{"1.5 + true;", "type mismatch: FLOAT + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"7 % 3;", 1},
+		{"-7 % 3;", -1},
+		{"10 % 5 == 0;", true},
+		{"7.5 % 2;", 1.5},
+		{"2 ** 10;", 1024},
+		{"2 ** 3 ** 2;", 512},
+		{"-2 ** 2;", -4},
+		{"(-2) ** 3;", -8},
+		{"5 ** 0;", 1},
+		{"2 ** -1;", 0.5},
+		{"4 ** 0.5;", 2.0},
+		{"2.5 ** 2;", 6.25},
+		{"6 & 3;", 2},
+		{"6 | 3;", 7},
+		{"6 ^ 3;", 5},
+		{"~5;", -6},
+		{"1 << 4;", 16},
+		{"-16 >> 2;", -4},
+		{"1 << 64;", 0},
+		{"2 + 3 & 1;", 3},
+		{"7 % 0;", errorMsg("modulo by zero")},
+		{"7.5 % 0;", errorMsg("modulo by zero")},
+		{"1 << -1;", errorMsg("negative shift count: -1")},
+		{"1.5 & 1;", errorMsg("unknown operator: FLOAT & INTEGER")},
+		{"~1.5;", errorMsg("unknown operator: ~FLOAT")},
+		{"true | false;", errorMsg("unknown operator: BOOLEAN | BOOLEAN")},
+	}
+
+	for i, tt := range tests {
+		evaluated := testEval(t, tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case errorMsg:
+			err, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("tests[%d] - object is not Error. got=%T (%+v)", i, evaluated, evaluated)
+				continue
+			}
+			if err.Message != string(expected) {
+				t.Errorf("tests[%d] - wrong error message. expected=%q, got=%q", i, expected, err.Message)
+			}
+		}
+	}
+}
+
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"true && true;", true},
+		{"true && false;", false},
+		{"false && true;", false},
+		{"true || false;", true},
+		{"false || false;", false},
+		{"1 < 2 && 2 < 3 || false;", true},
+		{"false || 1 > 2 && true;", false},
+		{"false && undefined;", false},
+		{"true || undefined;", true},
+		{"false && 1 / 0 == 1;", false},
+		{"true && 1;", errorMsg("expected BOOLEAN as right operand of && got: INTEGER")},
+		{`"a" || true;`, errorMsg("expected BOOLEAN as left operand of || got: STRING")},
+		{"true && undefined;", errorMsg("unknown identifier: undefined")},
+	}
+
+	for i, tt := range tests {
+		evaluated := testEval(t, tt.input)
+
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case errorMsg:
+			err, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("tests[%d] - object is not Error. got=%T (%+v)", i, evaluated, evaluated)
+				continue
+			}
+			if err.Message != string(expected) {
+				t.Errorf("tests[%d] - wrong error message. expected=%q, got=%q", i, expected, err.Message)
+			}
+		}
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -88,6 +242,10 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"1 < 2 != 4 >= 8;", true},
 		{"1 < 2 != 4 <= 8;", false},
 		{"1 < 2 == 4 <= 8;", true},
+		{"1 < 1.5;", true},
+		{"2.5 >= 3;", false},
+		{"1 == 1.0;", true},
+		{"0.1 + 0.2 != 0.3;", true},
 	}
 
 	for _, tt := range tests {
@@ -178,6 +336,82 @@ func TestIfElseStatements(t *testing.T) {
 	}
 }
 
+func TestElseIfAndIfExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"if (1 > 2) { 10; } else if (1 < 2) { 20; } else { 30; }", 20},
+		{"if (1 > 2) { 10; } else if (1 > 3) { 20; } else { 30; }", 30},
+		{"if (1 > 2) { 10; } else if (1 > 3) { 20; }", nil},
+		{"const x = if (true) { 10 } else { 20 }; x;", 10},
+		{"const x = if (false) { 10 } else { 20 }; x;", 20},
+		{"const x = if (false) { 10 } else if (true) { 20 } else { 30 }; x;", 20},
+		{"const x = if (true) { const y = 5; y * 2 } else { 0 }; x;", 10},
+		{"const x = if (true) { } else { 0 }; x;", nil},
+		{"const x = if (true) { const y = 2; } else { 3 }; x;", nil},
+		{"let y = 0; const x = if (true) { y = 2; } else { 3 }; x;", nil},
+		{"const x = if (true) { if (false) { 1 } else { 2 } } else { 3 }; x;", 2},
+		{"const x = if (false) { 1 } else { if (true) { 2 } else { 3 }; 4 }; x;", 4},
+		{"const x = if (true) { if (true) { 1; } 5 } else { 3 }; x;", 5},
+		{"const x = if (true) { 10 } else { 20 } + 5; x;", 15},
+		{"const abs = fun(x) { return if (x < 0) { -x } else { x }; }; abs(-7);", 7},
+		{"const x = if (true) { return 1; } else { 2 };", errorMsg("return statement not permitted in if-expression")},
+		{"const x = if (1) { 1 } else { 2 };", errorMsg("expected BOOLEAN as condition in if-expression got: INTEGER")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestForStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"for (x in [1, 2, 3]) { print(x); }", "1 \n2 \n3 \n"},
+		{"for (x in []) { print(x); }", ""},
+		{`for (k in {"b": 1, "a": 2, 2: 3, 1: 4, true: 5, false: 6, 2.5: 7, 0.5: 8}) { print(k); }`, "false \ntrue \n0.5 \n2.5 \n1 \n2 \na \nb \n"},
+		{"for (i in range(3)) { print(i); }", "0 \n1 \n2 \n"},
+		{"for (i in range(2, 4)) { print(i); }", "2 \n3 \n"},
+		{"for (i in range(5, 0, -2)) { print(i); }", "5 \n3 \n1 \n"},
+		{"for (i in range(0)) { print(i); }", ""},
+		{"for (i in range(10)) { if (i == 3) { break; } print(i); }", "0 \n1 \n2 \n"},
+		{"for (i in range(4)) { if (i % 2 == 0) { continue; } print(i); }", "1 \n3 \n"},
+		{"for (i in range(2)) { for (j in range(3)) { if (j == 1) { break; } print(i, j); } }", "0 0 \n1 0 \n"},
+		{"const x = 5; for (x in range(2)) { print(x); } print(x);", "0 \n1 \n5 \n"},
+		{"for (i in range(2)) { const y = i * 2; print(y); }", "0 \n2 \n"},
+		{"const f = fun() { for (i in range(10)) { if (i == 4) { return i; } } return -1; }; print(f());", "4 \n"},
+		{"for (x in 5) { print(x); }", errorMsg("expected ARRAY, HASH or RANGE in for statement got: INTEGER")},
+		{"for (x in range(1, 2, 0)) { print(x); }", errorMsg("range step cannot be zero")},
+		{`for (x in range("a")) { print(x); }`, errorMsg("argument to `range` not supported, got STRING")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		output := programOutput.String()
+		programOutput.Reset()
+
+		switch expected := tt.expected.(type) {
+		case string:
+			if output != expected {
+				t.Errorf("wrong output for %q. expected=%q, got=%q", tt.input, expected, output)
+			}
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -222,6 +456,54 @@ func TestReturnStatements(t *testing.T) {
 	}
 }
 
+func TestEvalProgramErrors(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+		expectedCode   diagnostic.Code
+		expectedPos    string
+		expectedEnd    string
+	}{
+		{"print(1);\nconst a = 5 + true;", "1 \n", diagnostic.TypeMismatch, "line: 2, column: 11", "line: 2, column: 19"},
+		{"const a = [1, foobar];", "", diagnostic.UnknownIdentifier, "line: 1, column: 15", "line: 1, column: 21"},
+		{"const a = 10 / 0;", "", diagnostic.DivisionByZero, "line: 1, column: 11", "line: 1, column: 17"},
+		{"[1, 2][5];", "", diagnostic.IndexOutOfRange, "line: 1, column: 1", "line: 1, column: 10"},
+		{"len(1, 2);", "", diagnostic.WrongArgumentCount, "line: 1, column: 1", "line: 1, column: 10"},
+		{"const f = fun() { 1; };\nf();", "", diagnostic.InvalidReturn, "line: 2, column: 1", "line: 2, column: 4"},
+		{"if (true) { return 1; }", "", diagnostic.InvalidReturn, "line: 1, column: 1", "line: 1, column: 24"},
+		{"1 |> len(2);", "", diagnostic.WrongArgumentCount, "line: 1, column: 6", "line: 1, column: 12"},
+		{"1 |> f;", "", diagnostic.UnknownIdentifier, "line: 1, column: 6", "line: 1, column: 7"},
+		{"const f = fun(x) {\n\treturn x / 0;\n};\n1 |> f;", "", diagnostic.DivisionByZero, "line: 2, column: 9", "line: 2, column: 14"},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("tests[%d] - parser errors: %q", i, p.Errors())
+		}
+
+		output, errors := EvalProgram(program, object.NewEnvironment())
+
+		if output != tt.expectedOutput {
+			t.Errorf("tests[%d] - output wrong. expected=%q, got=%q", i, tt.expectedOutput, output)
+		}
+		if len(errors) != 1 {
+			t.Fatalf("tests[%d] - expected 1 error, got=%d", i, len(errors))
+		}
+		if errors[0].Code != tt.expectedCode {
+			t.Errorf("tests[%d] - code wrong. expected=%q, got=%q", i, tt.expectedCode, errors[0].Code)
+		}
+		if errors[0].Pos.String() != tt.expectedPos {
+			t.Errorf("tests[%d] - pos wrong. expected=%q, got=%q", i, tt.expectedPos, errors[0].Pos)
+		}
+		if errors[0].End.String() != tt.expectedEnd {
+			t.Errorf("tests[%d] - end wrong. expected=%q, got=%q", i, tt.expectedEnd, errors[0].End)
+		}
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	tests := []struct {
 		input       string
@@ -364,6 +646,143 @@ func TestConstStatements(t *testing.T) {
 	}
 }
 
+func TestLetAndAssignStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let a = 5; a;", 5},
+		{"let a = 5; a = a * 2; a;", 10},
+		{"let a = 5; a = 7;", 7},
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { sum = sum + x; } sum;", 10},
+		{"let a = 1; if (true) { a = 2; } a;", 2},
+		{"let a = 1; if (true) { let a = 2; a = 3; } a;", 1},
+		{"const counter = fun() { let n = 0; return fun() { n = n + 1; return n; }; }; const next = counter(); next(); next(); next();", 3},
+		{"let a = 1; let a = 2;", errorMsg(`redeclared variable: "a" in one block`)},
+		{"const a = 1; let a = 2;", errorMsg(`redeclared variable: "a" in one block`)},
+		{"let a = 1; const a = 2;", errorMsg(`redeclared constant: "a" in one block`)},
+		{"const a = 1; const f = fun() { let a = 0; a = 2; return a; }; f();", 2},
+		{"const f = fun() { a = 2; }; const a = 1; f();", errorMsg(`cannot reassign constant: "a"`)},
+		{"b = 2;", errorMsg("assignment to undeclared variable: b")},
+		{"let a = 1; a = b;", errorMsg("unknown identifier: b")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const [a, b] = [1, 2]; a + b;", 3},
+		{"const [a] = [1, 2, 3]; a;", 1},
+		{"const [a, ...others] = [1, 2, 3]; len(others);", 2},
+		{"const [a, b, ...others] = [1, 2]; len(others);", 0},
+		{"const [a, b = a + 1] = [1]; b;", 2},
+		{"const [a, b = a + 1] = [1, 5]; b;", 5},
+		{`const {x, y} = {"x": 1, "y": 2}; x * 10 + y;`, 12},
+		{`const {x: one, "the y": two} = {"x": 1, "the y": 2}; two;`, 2},
+		{`const {1: one, z = 3} = {1: 10}; one + z;`, 13},
+		{`const {point: [x, y], size: {w}} = {"point": [1, 2], "size": {"w": 3}}; x + y + w;`, 6},
+		{`const [[a, b], {c}] = [[1, 2], {"c": 3}]; a + b + c;`, 6},
+		{`const f = fun([a, b], {c}) { return a + b + c; }; f([1, 2], {"c": 3});`, 6},
+		{"const [a, b, ...rest] = [1, 2, 3, 4]; len(rest);", 2},
+		{`const [first, {len}] = [1, {"len": 2}]; first + len;`, 3},
+		{"const f = fun([first]) { return first; }; f([5]) + first([1]);", 6},
+		{"const [a, b] = [1];", errorMsg("cannot destructure array of length 1, pattern expects at least 2 elements")},
+		{`const {x} = {"y": 1};`, errorMsg("cannot destructure hash without key: x")},
+		{"const [a] = 1;", errorMsg("cannot destructure INTEGER with array pattern")},
+		{"const {a} = [1];", errorMsg("cannot destructure ARRAY with hash pattern")},
+		{"const [a, a] = [1, 2];", errorMsg(`redeclared constant: "a" in one block`)},
+		{"const a = 1; const [a] = [2];", errorMsg(`redeclared constant: "a" in one block`)},
+		{"const [a = b] = [];", errorMsg("unknown identifier: b")},
+		{"const f = fun([a]) { return a; }; f(1);", errorMsg("cannot destructure INTEGER with array pattern")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const f = fun(a, b = 10) { return a + b; }; f(1);", 11},
+		{"const f = fun(a, b = 10) { return a + b; }; f(1, 2);", 3},
+		{"const f = fun(a, b = a * 2) { return b; }; f(4);", 8},
+		{"const n = 5; const f = fun(a = n) { return a; }; f();", 5},
+		{"const f = fun(a, ...others) { return len(others); }; f(1);", 0},
+		{"const f = fun(a, ...others) { return len(others); }; f(1, 2, 3);", 2},
+		{"const f = fun(...all) { return all[1]; }; f(1, 2, 3);", 2},
+		{"const f = fun(a, b = 2, ...others) { return a + b + len(others); }; f(1, 5, 0, 0);", 8},
+		{"const f = fun([a, b] = [1, 2]) { return a + b; }; f();", 3},
+		{"const f = fun(a, b = 10, ...rest) { return a + b + len(rest); }; f(1, 2, 3, 4);", 5},
+		{"const f = fun(a, b) { return a; }; f(1);", errorMsg("wrong number of arguments. got=1 want=2")},
+		{"const f = fun(a) { return a; }; f(1, 2);", errorMsg("wrong number of arguments. got=2 want=1")},
+		{"const f = fun() { return 1; }; f(1);", errorMsg("wrong number of arguments. got=1 want=0")},
+		{"const f = fun(a, b = 1) { return a; }; f();", errorMsg("wrong number of arguments. got=0 want=1 to 2")},
+		{"const f = fun(a, b = 1) { return a; }; f(1, 2, 3);", errorMsg("wrong number of arguments. got=3 want=1 to 2")},
+		{"const f = fun(a, b, ...others) { return a; }; f(1);", errorMsg("wrong number of arguments. got=1 want at least 2")},
+		{"const f = fun(a = b) { return a; }; f();", errorMsg("unknown identifier: b")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const double = x => x * 2; double(21);", 42},
+		{"const add = (a, b) => a + b; add(40, 2);", 42},
+		{"(() => 42)();", 42},
+		{"const add = (a, b = 2) => a + b; add(40);", 42},
+		{"const count = (...xs) => len(xs); count(1, 2, 3);", 3},
+		{"const adder = x => y => x + y; adder(40)(2);", 42},
+		{"const n = 40; const f = x => x + n; f(2);", 42},
+		{"const apply = fun(f, x) { return f(x); }; apply(x => x + 1, 41);", 42},
+		{"const abs = x => if (x < 0) { -x } else { x }; abs(-42);", 42},
+		{"const f = x => x; f();", errorMsg("wrong number of arguments. got=0 want=1")},
+		{`const f = x => x + "a"; f(1);`, errorMsg("type mismatch: INTEGER + STRING")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fun(x) { return x + 2; };"
 	expectedBody := "return (x + 2);"
@@ -438,6 +857,19 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	return true
 }
 
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+	result, ok := obj.(*object.Float)
+	if !ok {
+		t.Errorf("object is not Float. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf("Wrong Float value, expected=%g, got=%g", expected, result.Value)
+		return false
+	}
+	return true
+}
+
 func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 	result, ok := obj.(*object.Boolean)
 	if !ok {
@@ -515,6 +947,7 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`len("");`, 0},
 		{`len("four");`, 4},
 		{`len("hello world");`, 11},
+		{`len("zażółć");`, 6},
 		{`len(1);`, "argument to `len` not supported, got INTEGER"},
 		{`len("one", "two");`, "wrong number of arguments. got=2 want=1"},
 		{`len([1,2,3,4]);`, 4},
@@ -613,6 +1046,34 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc"[0];`, "a"},
+		{`"abc"[2];`, "c"},
+		{`"zażółć"[3];`, "ó"},
+		{`const s = "gęś"; s[len(s) - 1];`, "ś"},
+		{`"abc"[3];`, errorMsg("index out of boundaries")},
+		{`"abc"[-1];`, errorMsg("index out of boundaries")},
+		{`"abc"["a"];`, errorMsg("index operator not supported: STRING[STRING]")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case string:
+			testStringObject(t, evaluated, expected)
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+// errorMsg marks expected values that are error messages in tests where strings are valid results.
+type errorMsg string
+
 func TestHashLiterals(t *testing.T) {
 	input := `
 	const two = "two";
@@ -654,6 +1115,220 @@ func TestHashLiterals(t *testing.T) {
 	}
 }
 
+func TestInterpolatedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`const name = "Ann"; const age = 42; "Hello ${name}, you are ${age}";`, "Hello Ann, you are 42"},
+		{`"${1 + 2}${3.5}${true}";`, "33.5true"},
+		{`"${[1, "a"]}";`, "[1, a]"},
+		{`const n = 1; "${"inner ${n + 1}"} outer";`, "inner 2 outer"},
+		{`const greet = fun(name) { return "Hi ${name}!"; }; greet("Bob");`, "Hi Bob!"},
+		{`const x = 5; "${if (x > 3) { "big" } else { "small" }}";`, "big"},
+		{`"cost: $5, \${literal}";`, "cost: $5, ${literal}"},
+		{`"${unknown}";`, errorMsg("unknown identifier: unknown")},
+		{`"${1 + "a"}";`, errorMsg("type mismatch: INTEGER + STRING")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case string:
+			testStringObject(t, evaluated, expected)
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const a = [1, 2]; [...a, 3, ...a];", []int64{1, 2, 3, 1, 2}},
+		{"[...[]];", []int64{}},
+		{"const a = [1, 2]; const b = [...a]; a == b;", false},
+		{"const add = fun(a, b, c) { return a + b + c; }; add(...[1, 2, 3]);", 6},
+		{"const add = fun(a, b, c) { return a + b + c; }; add(1, ...[2], 3);", 6},
+		{"const count = fun(...xs) { return len(xs); }; count(...[1, 2], ...[3]);", 3},
+		{`const base = {"x": 1, "y": 2}; {...base, "y": 3}["y"];`, 3},
+		{`const base = {"x": 1, "y": 2}; {"y": 3, ...base}["y"];`, 2},
+		{`const base = {"x": 1}; const h = {...base, "y": 2}; h["x"] + h["y"];`, 3},
+		{`const base = {"x": 1}; const h = {...base, "y": 2}; base["y"];`, errorMsg(`No hash pair in "{x: 1}" with key "y"`)},
+		{"[...1];", errorMsg("expected ARRAY in spread got: INTEGER")},
+		{`[...{"a": 1}];`, errorMsg("expected ARRAY in spread got: HASH")},
+		{"const f = fun(x) { return x; }; f(...range(1));", errorMsg("expected ARRAY in spread got: RANGE")},
+		{`{..."a"};`, errorMsg("expected HASH in spread got: STRING")},
+		{`{...[1]};`, errorMsg("expected HASH in spread got: ARRAY")},
+		{"[...x];", errorMsg("unknown identifier: x")},
+		{"const add = fun(a, b) { return a + b; }; add(...[1, 2, 3]);", errorMsg("wrong number of arguments. got=3 want=2")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case []int64:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements. want=%d, got=%d", len(expected), len(array.Elements))
+				continue
+			}
+			for i, el := range expected {
+				testIntegerObject(t, array.Elements[i], el)
+			}
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestPipeExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const double = x => x * 2; 21 |> double;", 42},
+		{"const add = (a, b) => a + b; 40 |> add(2);", 42},
+		{"const double = x => x * 2; const add = (a, b) => a + b; 20 |> double |> add(2);", 42},
+		{"[1, 2, 3] |> len;", 3},
+		{"[1, 2, 3] |> rest |> first;", 2},
+		{"const double = x => x * 2; 20 + 1 |> double;", 42},
+		{"const sum = fun(...xs) { return len(xs); }; 1 |> sum(...[2, 3]);", 3},
+		{`const obj = {"inc": x => x + 1}; 41 |> obj.inc;`, 42},
+		{"5 |> (x => x * 2);", 10},
+		{"const add = (a, b) => a + b; 1 |> add(2, 3);", errorMsg("pipeline stage 1 (add(2, 3)) failed: wrong number of arguments. got=3 want=2")},
+		{"const double = x => x * 2; 1 |> double |> double |> len;", errorMsg("pipeline stage 3 (len) failed: argument to `len` not supported, got INTEGER")},
+		{"1 |> 2;", errorMsg("pipeline stage 1 (2) failed: not a function: INTEGER")},
+		{"1 |> unknown;", errorMsg("pipeline stage 1 (unknown) failed: unknown identifier: unknown")},
+		{"const add = (a, b) => a + b; 1 |> add(unknown);", errorMsg("pipeline stage 1 (add(unknown)) failed: unknown identifier: unknown")},
+		{"const add = (a, b) => a + b; 1 |> add(2) |> add(1 / 0);", errorMsg("pipeline stage 2 (add((1 / 0))) failed: division by zero")},
+		{"unknown |> len;", errorMsg("unknown identifier: unknown")},
+		{`const f = x => x + "a"; 1 |> f;`, errorMsg("pipeline stage 1 (f) failed: type mismatch: INTEGER + STRING")},
+		{`const f = x => x + "a"; const g = x => x |> f; 1 |> len |> g;`, errorMsg("pipeline stage 1 (len) failed: argument to `len` not supported, got INTEGER")},
+		{`const f = x => x + "a"; const g = x => x |> f; [1] |> len |> g;`, errorMsg("pipeline stage 2 (g) failed: pipeline stage 1 (f) failed: type mismatch: INTEGER + STRING")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	describe := `const describe = fun(v) {
+		return match (v) {
+			0 => "zero",
+			-1 => "minus one",
+			2.5 => "two and a half",
+			"hi" => "greeting",
+			true => "yes",
+			[] => "empty",
+			[x] => "one: " + x,
+			[1, ...others] => "starts with one, then ${others}",
+			{name, age} if age >= 18 => "adult " + name,
+			{"name": n} => "person " + n,
+			_ => "something else",
+		};
+	};
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{describe + "describe(0);", "zero"},
+		{describe + "describe(-1);", "minus one"},
+		{describe + "describe(2.5);", "two and a half"},
+		{describe + `describe("hi");`, "greeting"},
+		{describe + "describe(true);", "yes"},
+		{describe + "describe(false);", "something else"},
+		{describe + "describe([]);", "empty"},
+		{describe + `describe(["a"]);`, "one: a"},
+		{describe + "describe([1, 2, 3]);", "starts with one, then [2, 3]"},
+		{describe + "describe([2, 3]);", "something else"},
+		{describe + `describe({"name": "Ann", "age": 20});`, "adult Ann"},
+		{describe + `describe({"name": "Bob", "age": 10});`, "person Bob"},
+		{describe + `describe({"age": 10});`, "something else"},
+		{describe + "describe(1);", "something else"},
+		{describe + `describe("1");`, "something else"},
+		{"match (3) { n if n > 5 => n, n => n * 2 };", 6},
+		{`match (1.0) { 1 => "one", _ => "other" };`, "one"},
+		{`match (1) { 1.0 => "one", _ => "other" };`, "one"},
+		{`match (-2) { -2.0 => "minus two", _ => "other" };`, "minus two"},
+		{`match (1.5) { 1 => "one", _ => "other" };`, "other"},
+		{`match ([2.0, 3]) { [2, 3.0] => "both", _ => "other" };`, "both"},
+		{`match ("1") { 1 => "one", _ => "other" };`, "other"},
+		{`match (1) { "1" => "string", true => "bool", _ => "other" };`, "other"},
+		{"match ([1, [2, 3]]) { [a, [b, c]] => a + b + c };", 6},
+		{"match ([1, 2]) { [a, ...tail] => len(tail) };", 1},
+		{`match ({1: {"x": 5}}) { {1: {x}} => x };`, 5},
+		{"const x = 10; match (1) { x => x };", 1},
+		{"const x = 10; match (1) { _ => x };", 10},
+		{"const ok = true; match (1) { n if ok => n };", 1},
+		{"match (5) { 1 => 1 };", errorMsg("no case of match expression matched value: 5")},
+		{`match ([1]) { [a, b] => a };`, errorMsg("no case of match expression matched value: [1]")},
+		{"match (1) { n if n => n };", errorMsg("expected BOOLEAN as guard in match case got: INTEGER")},
+		{"match ([1, 1]) { [a, a] => a };", errorMsg(`redeclared constant: "a" in one block`)},
+		{"match (unknown) { _ => 1 };", errorMsg("unknown identifier: unknown")},
+		{`match (1) { n => n + "a" };`, errorMsg("type mismatch: INTEGER + STRING")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testStringObject(t, evaluated, expected)
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
+func TestFieldExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`const obj = {"name": "Jane"}; obj.name;`, "Jane"},
+		{`const obj = {"greet": fun(name) { return "Hi " + name; }}; obj.greet("Jane");`, "Hi Jane"},
+		{`const obj = {"inner": {"tags": ["a", "b"]}}; obj.inner.tags[1];`, "b"},
+		{`const obj = {"len": "field"}; obj.len;`, "field"},
+		{`const obj = {"make": fun() { return {"name": "made"}; }}; obj.make().name;`, "made"},
+		{`const obj = {"name": "Jane"}; obj.age;`, errorMsg(`no field "age" in hash`)},
+		{`const obj = {1: "one"}; obj.one;`, errorMsg(`no field "one" in hash`)},
+		{`const arr = [1]; arr.name;`, errorMsg("field access not supported: ARRAY.name")},
+		{`"abc".length;`, errorMsg("field access not supported: STRING.length")},
+		{`unknown.name;`, errorMsg("unknown identifier: unknown")},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		switch expected := tt.expected.(type) {
+		case string:
+			testStringObject(t, evaluated, expected)
+		case errorMsg:
+			testErrorObject(t, evaluated, string(expected))
+		}
+	}
+}
+
 func TestHashIndexExpressions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -666,6 +1341,8 @@ func TestHashIndexExpressions(t *testing.T) {
 		{`{5: 10}[5];`, 10},
 		{`{true: 5}[true];`, 5},
 		{`{false: 5}[false];`, 5},
+		{`{1.5: 5}[1.5];`, 5},
+		{`{0.0: 5}[-0.0];`, 5},
 	}
 
 	for _, tt := range tests {
@@ -1,51 +1,155 @@
 package lexer
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/token"
 )
 
+// Mode controls the optional behaviour of the Lexer.
+type Mode uint
+
+const (
+	// ScanComments makes the Lexer return comments as COMMENT tokens instead of skipping them.
+	ScanComments Mode = 1 << iota
+)
+
 // Lexer is a struct representing the lexical analyzer.
 type Lexer struct {
-	input        string
-	position     int
-	nextPosition int
-	ch           byte
-	RowNum       int
+	// Filename is used in positions of the returned tokens.
+	// It should be set before reading the first token.
+	Filename string
+	// Mode of the analysis, it can be changed between the tokens.
+	Mode Mode
+
+	input        *bufio.Reader
+	position     int // offset of the current character
+	nextPosition int // offset of the character after the current one
+	ch           rune
+	chBytes      []byte // encoding of the current character as found in the input
+	invalidChar  bool   // true if the current character is not valid UTF-8
+	line         int
+	column       int
+	errors       []diagnostic.Diagnostic
+	readFailed   bool
+
+	// interpolations opened in strings and not yet closed, the innermost one is the last
+	interpolations []interpolation
+
+	// raw text read since the start of the current token,
+	// only the current token is kept in memory
+	text bytes.Buffer
+}
+
+// interpolation is an expression embedded in a string literal with "${" and "}".
+type interpolation struct {
+	start  token.Position // position of the string containing the interpolation
+	braces int            // number of braces opened inside of the interpolation and not yet closed
 }
 
-// New creates new instance of the Lexer.
+// New creates new instance of the Lexer analyzing given program.
 func New(input string) *Lexer {
-	l := &Lexer{input: input, RowNum: 1}
+	return NewReader(strings.NewReader(input))
+}
+
+// NewReader creates new instance of the Lexer reading the program from r.
+// The input is read incrementally while the tokens are being requested.
+func NewReader(r io.Reader) *Lexer {
+	l := &Lexer{input: bufio.NewReader(r), line: 1, column: 1}
 	l.readChar()
 	return l
 }
 
-// Reads next char from the input.
+// Reads next UTF-8 encoded character from the input.
 // Increments values of position and nextPositon and advances the current character.
+// Keeps track of the line and column of the current character.
 func (l *Lexer) readChar() {
-	if l.nextPosition >= len(l.input) {
+	if l.ch == '\n' || l.ch == '\r' && l.peekChar() != '\n' {
+		l.line++
+		l.column = 1
+	} else if l.nextPosition > l.position {
+		l.column++
+	}
+
+	l.text.Write(l.chBytes)
+
+	buf := l.peekBytes()
+	size := 0
+	if len(buf) == 0 {
 		l.ch = 0
+		l.invalidChar = false
 	} else {
-		l.ch = l.input[l.nextPosition]
+		l.ch, size = utf8.DecodeRune(buf)
+		l.invalidChar = l.ch == utf8.RuneError && size == 1
 	}
+	l.chBytes = append(l.chBytes[:0], buf[:size]...)
+	l.input.Discard(size)
 	l.position = l.nextPosition
-	l.nextPosition++
+	l.nextPosition += size
 }
 
 // Returns next character from the input.
-func (l *Lexer) peekChar() byte {
-	if l.nextPosition >= len(l.input) {
+func (l *Lexer) peekChar() rune {
+	buf := l.peekBytes()
+	if len(buf) == 0 {
 		return 0
 	}
-	return l.input[l.nextPosition]
+	r, _ := utf8.DecodeRune(buf)
+	return r
+}
+
+// Returns the bytes of the next character without consuming them.
+// The returned slice may hold more than one character and is valid until the next read.
+// A failing reader is reported once and treated as the end of the input.
+func (l *Lexer) peekBytes() []byte {
+	if l.readFailed {
+		return nil
+	}
+	buf, err := l.input.Peek(utf8.UTFMax)
+	if len(buf) == 0 && err != nil && err != io.EOF {
+		l.readFailed = true
+		l.error(diagnostic.ReadError, l.pos(), "could not read the input: %s", err)
+	}
+	return buf
+}
+
+// Errors returns the lexical errors found so far.
+// Every error has a corresponding ILLEGAL token, after which the lexer carries on with the analysis.
+func (l *Lexer) Errors() []diagnostic.Diagnostic {
+	return l.errors
+}
+
+// Records a lexical error found at given position.
+func (l *Lexer) error(code diagnostic.Code, pos token.Position, format string, a ...interface{}) {
+	l.errors = append(l.errors, diagnostic.Diagnostic{
+		Severity: diagnostic.Error,
+		Code:     code,
+		Message:  fmt.Sprintf(format, a...),
+		Pos:      pos,
+	})
+}
+
+// Adds a hint to the last recorded error.
+func (l *Lexer) hint(hint string) {
+	last := &l.errors[len(l.errors)-1]
+	last.Hints = append(last.Hints, hint)
+}
+
+// Returns position of the current character.
+func (l *Lexer) pos() token.Position {
+	return token.Position{Filename: l.Filename, Offset: l.position, Line: l.line, Column: l.column}
 }
 
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		if l.ch == '\n' || l.ch == '\r' {
-			l.RowNum++
-		}
 		l.readChar()
 	}
 }
@@ -56,166 +160,495 @@ func (l *Lexer) skipOneLineComment() {
 	}
 }
 
-func (l *Lexer) skipMultipleLineComment() token.Token {
+// Skips the multiple line comment starting at the current character.
+// Returns false if the comment is not terminated.
+func (l *Lexer) skipMultipleLineComment() bool {
 	// skipping '/*'
 	l.readChar()
 	l.readChar()
 
 	for l.ch != 0 {
-		if l.ch == '*' {
-			if l.peekChar() == '/' {
-				l.readChar()
-				l.readChar()
-				return l.NextToken()
+		if l.ch == '*' && l.peekChar() == '/' {
+			l.readChar()
+			l.readChar()
+			return true
+		}
+		l.readChar()
+	}
+
+	return false
+}
+
+// NextToken analyzes text and returns the first token it founds.
+func (l *Lexer) NextToken() token.Token {
+	for {
+		l.skipWhitespace()
+		l.text.Reset()
+
+		if l.ch != '/' {
+			break
+		}
+		start := l.pos()
+		if l.peekChar() == '/' {
+			l.skipOneLineComment()
+		} else if l.peekChar() == '*' {
+			if !l.skipMultipleLineComment() {
+				tok := token.Token{Type: token.ILLEGAL, Literal: l.text.String(), Pos: start, End: l.pos()}
+				l.error(diagnostic.UnterminatedComment, start, "comment not terminated")
+				// the whole comment is marked, it reaches the end of input
+				l.errors[len(l.errors)-1].End = tok.End
+				l.hint(`multi line comments end with "*/"`)
+				return tok
 			}
+		} else {
+			break
 		}
 
-		if l.ch == '\n' || l.ch == '\r' {
-			l.RowNum++
+		if l.Mode&ScanComments != 0 {
+			return token.Token{Type: token.COMMENT, Literal: l.text.String(), Pos: start, End: l.pos()}
 		}
-		l.readChar()
 	}
 
-	msg := fmt.Sprintf("FATAL ERROR: comment not terminated at line: %d\n\n", l.RowNum)
+	start := l.pos()
+	errorCount := len(l.errors)
+	tok := l.readToken(start)
+	tok.Pos = start
+	tok.End = l.pos()
+	if tok.Type == token.ILLEGAL {
+		tok.Literal = l.text.String()
+	}
 
-	return token.Token{Type: token.ILLEGAL, Literal: msg, LineNumber: l.RowNum}
-}
+	// errors about the whole token span the token
+	for i := errorCount; i < len(l.errors); i++ {
+		if l.errors[i].Pos == start && !l.errors[i].End.IsValid() {
+			l.errors[i].End = tok.End
+		}
+	}
 
-// NextToken analyzes text and returns the first token it founds.
-func (l *Lexer) NextToken() (tok token.Token) {
-	l.skipWhitespace()
+	return tok
+}
 
+// readToken reads the token starting at the current character.
+func (l *Lexer) readToken(start token.Position) (tok token.Token) {
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
 			l.readChar()
-			tok = token.Token{Type: token.EQ, Literal: "==", LineNumber: l.RowNum}
+			tok = token.Token{Type: token.EQ, Literal: "=="}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: "=>"}
 		} else {
-			tok = newToken(token.ASSIGN, l.ch, l.RowNum)
+			tok = newToken(token.ASSIGN, l.ch)
 		}
 	case '+':
-		tok = newToken(token.PLUS, l.ch, l.RowNum)
+		tok = newToken(token.PLUS, l.ch)
 	case '-':
-		tok = newToken(token.MINUS, l.ch, l.RowNum)
+		tok = newToken(token.MINUS, l.ch)
 	case '!':
 		if l.peekChar() == '=' {
 			l.readChar()
-			tok = token.Token{Type: token.NEQ, Literal: "!=", LineNumber: l.RowNum}
+			tok = token.Token{Type: token.NEQ, Literal: "!="}
+		} else {
+			tok = newToken(token.BANG, l.ch)
+		}
+	case '&':
+		if l.peekChar() == '&' {
+			l.readChar()
+			tok = token.Token{Type: token.AND, Literal: "&&"}
 		} else {
-			tok = newToken(token.BANG, l.ch, l.RowNum)
+			tok = newToken(token.BIT_AND, l.ch)
 		}
+	case '|':
+		if l.peekChar() == '|' {
+			l.readChar()
+			tok = token.Token{Type: token.OR, Literal: "||"}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.PIPE, Literal: "|>"}
+		} else {
+			tok = newToken(token.BIT_OR, l.ch)
+		}
+	case '^':
+		tok = newToken(token.BIT_XOR, l.ch)
+	case '~':
+		tok = newToken(token.BIT_NOT, l.ch)
+	case '%':
+		tok = newToken(token.PERCENT, l.ch)
 	case '*':
-		tok = newToken(token.ASTERISK, l.ch, l.RowNum)
-	case '/':
-		if l.peekChar() == '/' {
-			l.skipOneLineComment()
-			return l.NextToken()
-		} else if l.peekChar() == '*' {
-			return l.skipMultipleLineComment()
+		if l.peekChar() == '*' {
+			l.readChar()
+			tok = token.Token{Type: token.POWER, Literal: "**"}
+		} else {
+			tok = newToken(token.ASTERISK, l.ch)
 		}
-		tok = newToken(token.SLASH, l.ch, l.RowNum)
+	case '/':
+		tok = newToken(token.SLASH, l.ch)
 	case '<':
 		if l.peekChar() == '=' {
 			l.readChar()
-			tok = token.Token{Type: token.LTE, Literal: "<=", LineNumber: l.RowNum}
+			tok = token.Token{Type: token.LTE, Literal: "<="}
+		} else if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.Token{Type: token.SHL, Literal: "<<"}
 		} else {
-			tok = newToken(token.LT, l.ch, l.RowNum)
+			tok = newToken(token.LT, l.ch)
 		}
 	case '>':
 		if l.peekChar() == '=' {
 			l.readChar()
-			tok = token.Token{Type: token.GTE, Literal: ">=", LineNumber: l.RowNum}
+			tok = token.Token{Type: token.GTE, Literal: ">="}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.SHR, Literal: ">>"}
 		} else {
-			tok = newToken(token.GT, l.ch, l.RowNum)
+			tok = newToken(token.GT, l.ch)
 		}
 	case ',':
-		tok = newToken(token.COMMA, l.ch, l.RowNum)
+		tok = newToken(token.COMMA, l.ch)
 	case ';':
-		tok = newToken(token.SEMICOLON, l.ch, l.RowNum)
+		tok = newToken(token.SEMICOLON, l.ch)
 	case '(':
-		tok = newToken(token.LPAREN, l.ch, l.RowNum)
+		tok = newToken(token.LPAREN, l.ch)
 	case ')':
-		tok = newToken(token.RPAREN, l.ch, l.RowNum)
+		tok = newToken(token.RPAREN, l.ch)
 	case '{':
-		tok = newToken(token.LBRACE, l.ch, l.RowNum)
+		if len(l.interpolations) > 0 {
+			l.interpolations[len(l.interpolations)-1].braces++
+		}
+		tok = newToken(token.LBRACE, l.ch)
 	case '}':
-		tok = newToken(token.RBRACE, l.ch, l.RowNum)
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1].braces == 0 {
+				stringStart := l.interpolations[n-1].start
+				l.interpolations = l.interpolations[:n-1]
+				return l.readString(stringStart, true)
+			}
+			l.interpolations[n-1].braces--
+		}
+		tok = newToken(token.RBRACE, l.ch)
 	case '[':
-		tok = newToken(token.LBRACKET, l.ch, l.RowNum)
+		tok = newToken(token.LBRACKET, l.ch)
 	case ']':
-		tok = newToken(token.RBRACKET, l.ch, l.RowNum)
+		tok = newToken(token.RBRACKET, l.ch)
 	case ':':
-		tok = newToken(token.COLON, l.ch, l.RowNum)
+		tok = newToken(token.COLON, l.ch)
+	case '.':
+		if bytes.HasPrefix(l.peekBytes(), []byte("..")) {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case '"':
-		return l.readString()
+		return l.readString(start, false)
+	case '`':
+		return l.readRawString(start)
 	case 0:
+		if len(l.interpolations) > 0 {
+			return l.unterminatedInterpolation()
+		}
 		tok.Literal = ""
 		tok.Type = token.EOF
-		tok.LineNumber = l.RowNum
+		return tok
 	default:
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdent()
 			// check if the read identifier is a keyword
 			tok.Type = token.LookUpIdent(tok.Literal)
-			tok.LineNumber = l.RowNum
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
-			tok.LineNumber = l.RowNum
-			return tok
-		} else {
-			msg := fmt.Sprintf("FATAL ERROR: illegal character: %q at line: %d\n\n", string(l.ch), l.RowNum)
-			tok = token.Token{Type: token.ILLEGAL, Literal: msg, LineNumber: l.RowNum}
+			return l.readNumber(start)
 		}
+		return l.readIllegal(start)
 	}
 	l.readChar()
 	return tok
 }
 
+// Reports the current character as not belonging to the language and skips it.
+func (l *Lexer) readIllegal(start token.Position) token.Token {
+	if l.invalidChar {
+		l.error(diagnostic.InvalidEncoding, start, "invalid UTF-8 encoding")
+	} else {
+		l.error(diagnostic.IllegalCharacter, start, "illegal character: %q", string(l.ch))
+	}
+	l.readChar()
+	return token.Token{Type: token.ILLEGAL}
+}
+
 // Keep reading input as long as it's a word.
 func (l *Lexer) readIdent() string {
-	position := l.position
 	for isLetter(l.ch) {
 		l.readChar()
 	}
-	return l.input[position:l.position]
+	return l.text.String()
 }
 
 // Keep reading as long as the input's a number.
-func (l *Lexer) readNumber() string {
-	position := l.position
-	for isDigit(l.ch) {
+// Reads decimal integer literals, e.g. 42 or 1_000_000, integer literals with a base prefix, e.g. 0xFF, 0b1010 or 0o755,
+// and floating-point literals, e.g. 3.14, 1e-3 or 2.5E+10.
+func (l *Lexer) readNumber(start token.Position) token.Token {
+	tokenType := token.Type(token.INT)
+	isBaseDigit := isDigit
+	malformed := false
+
+	if l.ch == '0' && isBasePrefix(l.peekChar()) {
+		l.readChar()
+		switch l.ch {
+		case 'x', 'X':
+			isBaseDigit = isHexDigit
+		case 'b', 'B':
+			isBaseDigit = isBinaryDigit
+		case 'o', 'O':
+			isBaseDigit = isOctalDigit
+		}
+		l.readChar()
+		malformed = !l.readDigits(isBaseDigit)
+	} else {
+		l.readDigits(isDigit)
+
+		if l.ch == '.' && isDigit(l.peekChar()) {
+			tokenType = token.FLOAT
+			l.readChar()
+			l.readDigits(isDigit)
+		}
+
+		if l.ch == 'e' || l.ch == 'E' {
+			peek := l.peekChar()
+			if isDigit(peek) || peek == '+' || peek == '-' {
+				tokenType = token.FLOAT
+				l.readChar()
+				if l.ch == '+' || l.ch == '-' {
+					l.readChar()
+				}
+				malformed = !l.readDigits(isDigit)
+			}
+		}
+	}
+
+	// letters and digits glued to the number are treated as a part of it, e.g. 0b102 or 12abc
+	for isLetter(l.ch) || isDigit(l.ch) {
+		malformed = true
+		l.readChar()
+	}
+
+	literal := l.text.String()
+	if malformed || !hasValidUnderscores(literal, isBaseDigit) {
+		l.error(diagnostic.MalformedNumber, start, "malformed number literal: %q", literal)
+		return token.Token{Type: token.ILLEGAL}
+	}
+
+	// leading zeros would make the literal octal in C-like languages, e.g. 010, octals are written with the 0o prefix
+	if tokenType == token.INT && len(literal) > 1 && literal[0] == '0' && (isDigit(rune(literal[1])) || literal[1] == '_') {
+		l.error(diagnostic.MalformedNumber, start, "malformed number literal: %q", literal)
+		l.hint("decimal integers can't start with 0, use the 0o prefix for octal numbers")
+		return token.Token{Type: token.ILLEGAL}
+	}
+
+	return token.Token{Type: tokenType, Literal: literal}
+}
+
+// Reads digits accepted by isBaseDigit and underscores separating them.
+// Returns false if there was no digit to read.
+func (l *Lexer) readDigits(isBaseDigit func(rune) bool) bool {
+	read := false
+	for isBaseDigit(l.ch) || l.ch == '_' {
+		read = read || l.ch != '_'
+		l.readChar()
+	}
+	return read
+}
+
+// Checks if every underscore in the number literal separates two digits or follows the base prefix.
+func hasValidUnderscores(literal string, isBaseDigit func(rune) bool) bool {
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '_' {
+			continue
+		}
+		afterPrefix := i == 2 && literal[0] == '0' && isBasePrefix(rune(literal[1]))
+		if i == 0 || !afterPrefix && !isBaseDigit(rune(literal[i-1])) {
+			return false
+		}
+		if i+1 == len(literal) || !isBaseDigit(rune(literal[i+1])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports the outermost string with interpolations not closed until the end of input as not terminated.
+func (l *Lexer) unterminatedInterpolation() token.Token {
+	l.error(diagnostic.UnterminatedString, l.interpolations[0].start, "string literal not terminated")
+	l.hint(`interpolated expressions end with "}"`)
+	l.interpolations = nil
+
+	return token.Token{Type: token.ILLEGAL}
+}
+
+// Reads the double-quoted string literal and decodes its escape sequences.
+// Every invalid escape sequence is reported, the string is read up to its closing quote anyway.
+// A string containing interpolations is split into parts at every "${" and the "}" closing it,
+// continued is true when reading the part following the closing "}".
+// The parts are returned even if they contain invalid escapes, so the interpolated expressions can still be parsed.
+func (l *Lexer) readString(start token.Position, continued bool) token.Token {
+	var out bytes.Buffer
+	valid := true
+
+	for {
+		l.readChar()
+		switch l.ch {
+		case '"':
+			l.readChar()
+			if continued {
+				return token.Token{Type: token.STRING_TAIL, Literal: out.String()}
+			}
+			if !valid {
+				return token.Token{Type: token.ILLEGAL}
+			}
+			return token.Token{Type: token.STRING, Literal: out.String()}
+		case '$':
+			if l.peekChar() != '{' {
+				out.WriteRune(l.ch)
+				continue
+			}
+			l.readChar()
+			l.readChar()
+			l.interpolations = append(l.interpolations, interpolation{start: start})
+			if continued {
+				return token.Token{Type: token.STRING_MIDDLE, Literal: out.String()}
+			}
+			return token.Token{Type: token.STRING_HEAD, Literal: out.String()}
+		case 0:
+			if len(l.interpolations) > 0 {
+				// the string opened inside of an interpolation, most likely its quote was meant to close the outer string
+				return l.unterminatedInterpolation()
+			}
+			l.error(diagnostic.UnterminatedString, start, "string literal not terminated")
+
+			return token.Token{Type: token.ILLEGAL}
+		case '\\':
+			escapePos := l.pos()
+			if msg := l.readEscape(&out); msg != "" {
+				l.error(diagnostic.InvalidEscape, escapePos, "%s", msg)
+				l.hint(`supported escape sequences are \", \\, \$, \n, \t, \r and \u{XXXX}`)
+				valid = false
+			}
+		default:
+			if l.invalidChar {
+				l.error(diagnostic.InvalidEncoding, l.pos(), "invalid UTF-8 encoding")
+				valid = false
+			}
+			out.WriteRune(l.ch)
+		}
+	}
+}
+
+// Decodes the escape sequence starting at the current backslash and writes it to out.
+// Leaves the lexer at the last character of the sequence.
+// Returns description of the problem if the sequence is invalid.
+func (l *Lexer) readEscape(out *bytes.Buffer) string {
+	l.readChar()
+
+	switch l.ch {
+	case '"':
+		out.WriteByte('"')
+	case '\\':
+		out.WriteByte('\\')
+	case '$':
+		out.WriteByte('$')
+	case 'n':
+		out.WriteByte('\n')
+	case 't':
+		out.WriteByte('\t')
+	case 'r':
+		out.WriteByte('\r')
+	case 'u':
+		return l.readUnicodeEscape(out)
+	case 0:
+		// let the caller report the unterminated string
+		return ""
+	default:
+		return fmt.Sprintf(`invalid escape sequence: "\%c"`, l.ch)
+	}
+
+	return ""
+}
+
+// Decodes "\u{XXXX}" escape sequence, the current character is 'u'.
+func (l *Lexer) readUnicodeEscape(out *bytes.Buffer) string {
+	if l.peekChar() != '{' {
+		return `invalid unicode escape sequence: expected "{" after "\u"`
+	}
+	l.readChar()
+
+	var digits bytes.Buffer
+	for isHexDigit(l.peekChar()) {
 		l.readChar()
+		digits.WriteRune(l.ch)
+	}
+
+	if l.peekChar() != '}' {
+		return fmt.Sprintf(`invalid unicode escape sequence: "\u{%s": expected hexadecimal digits and "}"`, digits.String())
+	}
+	l.readChar()
+
+	code, err := strconv.ParseUint(digits.String(), 16, 32)
+	if err != nil || digits.Len() > 6 || code > 0x10FFFF || 0xD800 <= code && code <= 0xDFFF {
+		return fmt.Sprintf(`invalid unicode code point: "\u{%s}"`, digits.String())
 	}
-	return l.input[position:l.position]
+	out.WriteRune(rune(code))
+
+	return ""
 }
 
-func (l *Lexer) readString() token.Token {
-	position := l.position + 1
+// Reads the backtick-quoted string literal, its content is taken as-is.
+func (l *Lexer) readRawString(start token.Position) token.Token {
 	for {
 		l.readChar()
-		if l.ch == '"' {
+		if l.ch == '`' {
 			break
 		} else if l.ch == 0 {
-			msg := fmt.Sprintf("FATAL ERROR: string literal not terminated at line: %d\n\n", l.RowNum)
+			l.error(diagnostic.UnterminatedString, start, "raw string literal not terminated")
 
-			return token.Token{Type: token.ILLEGAL, Literal: msg, LineNumber: l.RowNum}
+			return token.Token{Type: token.ILLEGAL}
 		}
 	}
 	l.readChar()
-	return token.Token{Type: token.STRING, Literal: l.input[position : l.position-1], LineNumber: l.RowNum}
+	literal := l.text.String()
+	return token.Token{Type: token.STRING, Literal: literal[1 : len(literal)-1]}
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func isOctalDigit(ch rune) bool {
+	return '0' <= ch && ch <= '7'
+}
+
+func isBinaryDigit(ch rune) bool {
+	return ch == '0' || ch == '1'
+}
+
+func isBasePrefix(ch rune) bool {
+	switch ch {
+	case 'x', 'X', 'b', 'B', 'o', 'O':
+		return true
+	}
+	return false
+}
+
 // create new token with given values
-func newToken(tokenType token.Type, ch byte, lineNum int) token.Token {
-	return token.Token{Type: tokenType, Literal: string(ch), LineNumber: lineNum}
+func newToken(tokenType token.Type, ch rune) token.Token {
+	return token.Token{Type: tokenType, Literal: string(ch)}
 }
@@ -1,7 +1,13 @@
 package lexer
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/radlinskii/interpreter/token"
 )
@@ -235,6 +241,170 @@ func TestNextToken5(t *testing.T) {
 	}
 }
 
+func TestLogicalOperatorTokens(t *testing.T) {
+	input := `a && b || !c & d |> e | f`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.OR, "||"},
+		{token.BANG, "!"},
+		{token.IDENT, "c"},
+		{token.BIT_AND, "&"},
+		{token.IDENT, "d"},
+		{token.PIPE, "|>"},
+		{token.IDENT, "e"},
+		{token.BIT_OR, "|"},
+		{token.IDENT, "f"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestLoopTokens(t *testing.T) {
+	input := `for (x in xs) { break; continue; } format`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.FOR, "for"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.IN, "in"},
+		{token.IDENT, "xs"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.BREAK, "break"},
+		{token.SEMICOLON, ";"},
+		{token.CONTINUE, "continue"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "format"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestMatchTokens(t *testing.T) {
+	input := `match (x) { [a] if a => a, _ => 0 }`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.RBRACKET, "]"},
+		{token.IF, "if"},
+		{token.IDENT, "a"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.ARROW, "=>"},
+		{token.INT, "0"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestArithmeticAndBitwiseOperatorTokens(t *testing.T) {
+	input := `a % b ** c * d & e | f ^ ~g << h >> i <= j >= k < l > m`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.PERCENT, "%"},
+		{token.IDENT, "b"},
+		{token.POWER, "**"},
+		{token.IDENT, "c"},
+		{token.ASTERISK, "*"},
+		{token.IDENT, "d"},
+		{token.BIT_AND, "&"},
+		{token.IDENT, "e"},
+		{token.BIT_OR, "|"},
+		{token.IDENT, "f"},
+		{token.BIT_XOR, "^"},
+		{token.BIT_NOT, "~"},
+		{token.IDENT, "g"},
+		{token.SHL, "<<"},
+		{token.IDENT, "h"},
+		{token.SHR, ">>"},
+		{token.IDENT, "i"},
+		{token.LTE, "<="},
+		{token.IDENT, "j"},
+		{token.GTE, ">="},
+		{token.IDENT, "k"},
+		{token.LT, "<"},
+		{token.IDENT, "l"},
+		{token.GT, ">"},
+		{token.IDENT, "m"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
 func TestSkipMultilineComment(t *testing.T) {
 	input := `/* multiple
 	line
@@ -347,6 +517,73 @@ func TestArrayTokens(t *testing.T) {
 	}
 }
 
+func TestArrowToken(t *testing.T) {
+	input := `x => x == y = >`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.IDENT, "x"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "x"},
+		{token.EQ, "=="},
+		{token.IDENT, "y"},
+		{token.ASSIGN, "="},
+		{token.GT, ">"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestEllipsisToken(t *testing.T) {
+	input := `[a, ...b] .. . obj.name`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "b"},
+		{token.RBRACKET, "]"},
+		{token.DOT, "."},
+		{token.DOT, "."},
+		{token.DOT, "."},
+		{token.IDENT, "obj"},
+		{token.DOT, "."},
+		{token.IDENT, "name"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
 func TestHashTokens(t *testing.T) {
 	input := `{"key": "value", 1: "anotherValue"};`
 
@@ -380,3 +617,527 @@ func TestHashTokens(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "const a = 5;\r\n/* comment\n*/ a >= \"str\";"
+
+	tests := []struct {
+		expectedType  token.Type
+		expectedPos   token.Position
+		expectedEndCl int
+	}{
+		{token.CONST, token.Position{Filename: "test.jnr", Offset: 0, Line: 1, Column: 1}, 6},
+		{token.IDENT, token.Position{Filename: "test.jnr", Offset: 6, Line: 1, Column: 7}, 8},
+		{token.ASSIGN, token.Position{Filename: "test.jnr", Offset: 8, Line: 1, Column: 9}, 10},
+		{token.INT, token.Position{Filename: "test.jnr", Offset: 10, Line: 1, Column: 11}, 12},
+		{token.SEMICOLON, token.Position{Filename: "test.jnr", Offset: 11, Line: 1, Column: 12}, 13},
+		{token.IDENT, token.Position{Filename: "test.jnr", Offset: 28, Line: 3, Column: 4}, 5},
+		{token.GTE, token.Position{Filename: "test.jnr", Offset: 30, Line: 3, Column: 6}, 8},
+		{token.STRING, token.Position{Filename: "test.jnr", Offset: 33, Line: 3, Column: 9}, 14},
+		{token.SEMICOLON, token.Position{Filename: "test.jnr", Offset: 38, Line: 3, Column: 14}, 15},
+		{token.EOF, token.Position{Filename: "test.jnr", Offset: 39, Line: 3, Column: 15}, 15},
+	}
+
+	l := New(input)
+	l.Filename = "test.jnr"
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Pos != tt.expectedPos {
+			t.Fatalf("tests[%d] - position wrong. expected=%+v, got=%+v", i, tt.expectedPos, tok.Pos)
+		}
+		if tok.End.Line != tt.expectedPos.Line || tok.End.Column != tt.expectedEndCl {
+			t.Fatalf("tests[%d] - end position wrong. expected column=%d, got=%+v", i, tt.expectedEndCl, tok.End)
+		}
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	input := `
+	"say \"hi\"";
+	"a\\b\tc\r\n";
+	"\u{41}\u{142}\u{1F600}";
+	` + "`raw \\n \"string\"`;" + `
+	"multi
+line";
+	` + "`multi\nline raw`;"
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.STRING, `say "hi"`},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "a\\b\tc\r\n"},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "Ał😀"},
+		{token.SEMICOLON, ";"},
+		{token.STRING, `raw \n "string"`},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "multi\nline"},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "multi\nline raw"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestInterpolatedStringTokens(t *testing.T) {
+	input := `"Hi ${name}, ${ {"a": "${x}"}["a"] }!" "$5 \${x}" "${"}"}"`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedColumn  int
+	}{
+		{token.STRING_HEAD, "Hi ", 1},
+		{token.IDENT, "name", 7},
+		{token.STRING_MIDDLE, ", ", 11},
+		{token.LBRACE, "{", 17},
+		{token.STRING, "a", 18},
+		{token.COLON, ":", 21},
+		{token.STRING_HEAD, "", 23},
+		{token.IDENT, "x", 26},
+		{token.STRING_TAIL, "", 27},
+		{token.RBRACE, "}", 29},
+		{token.LBRACKET, "[", 30},
+		{token.STRING, "a", 31},
+		{token.RBRACKET, "]", 34},
+		{token.STRING_TAIL, "!", 36},
+		{token.STRING, "$5 ${x}", 40},
+		{token.STRING_HEAD, "", 51},
+		{token.STRING, "}", 54},
+		{token.STRING_TAIL, "", 57},
+		{token.EOF, "", 59},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Pos.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - column wrong. expected=%d, got=%d", i, tt.expectedColumn, tok.Pos.Column)
+		}
+	}
+	if len(l.Errors()) != 0 {
+		t.Fatalf("unexpected errors: %q", l.Errors())
+	}
+}
+
+func TestUnterminatedInterpolation(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`"a ${x`, "string literal not terminated at line: 1, column: 1"},
+		{`"a ${x} b`, "string literal not terminated at line: 1, column: 1"},
+		{`"a ${"b ${x`, "string literal not terminated at line: 1, column: 1"},
+		{`"a ${x";`, "string literal not terminated at line: 1, column: 1"},
+		{`"a ${"b ${x} c`, "string literal not terminated at line: 1, column: 1"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+		for tok.Type != token.EOF && tok.Type != token.ILLEGAL {
+			tok = l.NextToken()
+		}
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("tests[%d] - expected ILLEGAL token. got=%q", i, tok.Type)
+		}
+		if len(l.Errors()) != 1 || l.Errors()[0].Error() != tt.expectedError {
+			t.Fatalf("tests[%d] - errors wrong. expected=[%q], got=%q", i, tt.expectedError, l.Errors())
+		}
+		if next := l.NextToken(); next.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF after the error. got=%q", i, next.Type)
+		}
+	}
+}
+
+func TestInvalidEscapes(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`"bad \q escape"`, "invalid escape sequence: \"\\q\" at line: 1, column: 6"},
+		{`"\u0041"`, "invalid unicode escape sequence: expected \"{\" after \"\\u\" at line: 1, column: 2"},
+		{`"\u{41"`, "invalid unicode escape sequence: \"\\u{41\": expected hexadecimal digits and \"}\" at line: 1, column: 2"},
+		{`"\u{D800}"`, "invalid unicode code point: \"\\u{D800}\" at line: 1, column: 2"},
+		{`"\u{110000}"`, "invalid unicode code point: \"\\u{110000}\" at line: 1, column: 2"},
+		{"`raw", "raw string literal not terminated at line: 1, column: 1"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, token.ILLEGAL, tok.Type)
+		}
+		if tok.Literal != tt.input {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.input, tok.Literal)
+		}
+		if len(l.Errors()) != 1 || l.Errors()[0].Error() != tt.expectedError {
+			t.Fatalf("tests[%d] - errors wrong. expected=[%q], got=%q", i, tt.expectedError, l.Errors())
+		}
+		if next := l.NextToken(); next.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected the whole string to be consumed. got=%q", i, next.Type)
+		}
+	}
+}
+
+func TestUnicodeInput(t *testing.T) {
+	input := `const zażółć = "gęślą jaźń"; żółw;`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedColumn  int
+	}{
+		{token.CONST, "const", 1},
+		{token.IDENT, "zażółć", 7},
+		{token.ASSIGN, "=", 14},
+		{token.STRING, "gęślą jaźń", 16},
+		{token.SEMICOLON, ";", 28},
+		{token.IDENT, "żółw", 30},
+		{token.SEMICOLON, ";", 34},
+		{token.EOF, "", 35},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Pos.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - column wrong. expected=%d, got=%d", i, tt.expectedColumn, tok.Pos.Column)
+		}
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"\xff", "invalid UTF-8 encoding at line: 1, column: 1"},
+		{"\"ab\xffc\"", "invalid UTF-8 encoding at line: 1, column: 4"},
+		{"€", "illegal character: \"€\" at line: 1, column: 1"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, token.ILLEGAL, tok.Type)
+		}
+		if tok.Literal != tt.input {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.input, tok.Literal)
+		}
+		if len(l.Errors()) != 1 || l.Errors()[0].Error() != tt.expectedError {
+			t.Fatalf("tests[%d] - errors wrong. expected=[%q], got=%q", i, tt.expectedError, l.Errors())
+		}
+	}
+}
+
+func TestNumberTokens(t *testing.T) {
+	input := `5 3.14 0.5 1e3 1e-3 2.5E+10 7.foo 1e+x`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.INT, "5"},
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "0.5"},
+		{token.FLOAT, "1e3"},
+		{token.FLOAT, "1e-3"},
+		{token.FLOAT, "2.5E+10"},
+		{token.INT, "7"},
+		{token.DOT, "."},
+		{token.IDENT, "foo"},
+		{token.ILLEGAL, "1e+x"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestIntegerLiteralForms(t *testing.T) {
+	input := `0xFF 0XaB_cd 0b1010 0B1_0 0o755 0O7_7 1_000_000 1_000.000_1 1e1_0 0x_1F`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.INT, "0xFF"},
+		{token.INT, "0XaB_cd"},
+		{token.INT, "0b1010"},
+		{token.INT, "0B1_0"},
+		{token.INT, "0o755"},
+		{token.INT, "0O7_7"},
+		{token.INT, "1_000_000"},
+		{token.FLOAT, "1_000.000_1"},
+		{token.FLOAT, "1e1_0"},
+		{token.INT, "0x_1F"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	tests := []string{"0x", "0b102", "0o8", "0xFG", "1__000", "1000_", "1_.5", "1e_5", "12abc", "0b_", "0x1__2", "010", "09", "00", "0_7"}
+
+	for i, input := range tests {
+		l := New(input)
+		tok := l.NextToken()
+
+		expectedError := fmt.Sprintf("malformed number literal: %q at line: 1, column: 1", input)
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q (%q)", i, token.ILLEGAL, tok.Type, tok.Literal)
+		}
+		if tok.Literal != input {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, input, tok.Literal)
+		}
+		if len(l.Errors()) != 1 || l.Errors()[0].Error() != expectedError {
+			t.Fatalf("tests[%d] - errors wrong. expected=[%q], got=%q", i, expectedError, l.Errors())
+		}
+	}
+}
+
+func TestRecoveryAfterLexicalErrors(t *testing.T) {
+	input := `const a = 5 $ 3;
+	const b = "bad \q" + 0b12;
+	const c = # "ok";
+	/* not terminated`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.CONST, "const"},
+		{token.IDENT, "a"},
+		{token.ASSIGN, "="},
+		{token.INT, "5"},
+		{token.ILLEGAL, "$"},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.CONST, "const"},
+		{token.IDENT, "b"},
+		{token.ASSIGN, "="},
+		{token.ILLEGAL, `"bad \q"`},
+		{token.PLUS, "+"},
+		{token.ILLEGAL, "0b12"},
+		{token.SEMICOLON, ";"},
+		{token.CONST, "const"},
+		{token.IDENT, "c"},
+		{token.ASSIGN, "="},
+		{token.ILLEGAL, "#"},
+		{token.STRING, "ok"},
+		{token.SEMICOLON, ";"},
+		{token.ILLEGAL, "/* not terminated"},
+		{token.EOF, ""},
+	}
+
+	expectedErrors := []string{
+		`illegal character: "$" at line: 1, column: 13`,
+		`invalid escape sequence: "\q" at line: 2, column: 17`,
+		`malformed number literal: "0b12" at line: 2, column: 23`,
+		`illegal character: "#" at line: 3, column: 12`,
+		`comment not terminated at line: 4, column: 2`,
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+
+	if len(l.Errors()) != len(expectedErrors) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%d (%q)", len(expectedErrors), len(l.Errors()), l.Errors())
+	}
+	for i, err := range l.Errors() {
+		if err.Error() != expectedErrors[i] {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, expectedErrors[i], err.Error())
+		}
+	}
+}
+
+func TestReaderMatchesStringLexer(t *testing.T) {
+	input := "const ąę = fun(x, y) {\r\n\treturn x + y; // sum\r};\n" +
+		"/* multi\nline */ const s = \"tab\\t\\u{1F600} \xff\";\n" +
+		"const r = `raw\nstring`; [1_000, 0xFF, 3.14e-2, 1e+x, 0b12][0] >= 2 != !true;\n" +
+		"{\"a\": 1}[\"a\"] $ \"not terminated"
+
+	expected := New(input)
+	readers := map[string]io.Reader{
+		"whole":    strings.NewReader(input),
+		"byte":     iotest.OneByteReader(strings.NewReader(input)),
+		"half":     iotest.HalfReader(strings.NewReader(input)),
+		"data+eof": iotest.DataErrReader(strings.NewReader(input)),
+	}
+
+	var tokens []token.Token
+	for {
+		tok := expected.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	for name, r := range readers {
+		l := NewReader(r)
+		for i, expectedToken := range tokens {
+			tok := l.NextToken()
+			if tok != expectedToken {
+				t.Fatalf("%s: tokens[%d] wrong. expected=%+v, got=%+v", name, i, expectedToken, tok)
+			}
+		}
+		if !reflect.DeepEqual(l.Errors(), expected.Errors()) {
+			t.Errorf("%s: errors wrong. expected=%q, got=%q", name, expected.Errors(), l.Errors())
+		}
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("disk failure")
+}
+
+func TestReaderError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("const a = 1;\nconst"), failingReader{})
+	l := NewReader(iotest.OneByteReader(r))
+
+	expected := []token.Type{token.CONST, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON, token.CONST, token.EOF, token.EOF}
+	for i, tt := range expected {
+		tok := l.NextToken()
+		if tok.Type != tt {
+			t.Fatalf("tokens[%d] - tokentype wrong. expected=%q, got=%q", i, tt, tok.Type)
+		}
+	}
+
+	expectedError := "could not read the input: disk failure at line: 2, column: 6"
+	if len(l.Errors()) != 1 || l.Errors()[0].Error() != expectedError {
+		t.Fatalf("errors wrong. expected=[%q], got=%q", expectedError, l.Errors())
+	}
+}
+
+func TestScanComments(t *testing.T) {
+	input := `// lead
+const a = 1; // trailing
+/* multi
+line */ a;
+/* not terminated`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedPos     string
+		expectedEnd     string
+	}{
+		{token.COMMENT, "// lead", "line: 1, column: 1", "line: 1, column: 8"},
+		{token.CONST, "const", "line: 2, column: 1", "line: 2, column: 6"},
+		{token.IDENT, "a", "line: 2, column: 7", "line: 2, column: 8"},
+		{token.ASSIGN, "=", "line: 2, column: 9", "line: 2, column: 10"},
+		{token.INT, "1", "line: 2, column: 11", "line: 2, column: 12"},
+		{token.SEMICOLON, ";", "line: 2, column: 12", "line: 2, column: 13"},
+		{token.COMMENT, "// trailing", "line: 2, column: 14", "line: 2, column: 25"},
+		{token.COMMENT, "/* multi\nline */", "line: 3, column: 1", "line: 4, column: 8"},
+		{token.IDENT, "a", "line: 4, column: 9", "line: 4, column: 10"},
+		{token.SEMICOLON, ";", "line: 4, column: 10", "line: 4, column: 11"},
+		{token.ILLEGAL, "/* not terminated", "line: 5, column: 1", "line: 5, column: 18"},
+		{token.EOF, "", "line: 5, column: 18", "line: 5, column: 18"},
+	}
+
+	l := New(input)
+	l.Mode = ScanComments
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Pos.String() != tt.expectedPos {
+			t.Fatalf("tests[%d] - pos wrong. expected=%q, got=%q", i, tt.expectedPos, tok.Pos)
+		}
+		if tok.End.String() != tt.expectedEnd {
+			t.Fatalf("tests[%d] - end wrong. expected=%q, got=%q", i, tt.expectedEnd, tok.End)
+		}
+	}
+
+	if len(l.Errors()) != 1 {
+		t.Fatalf("wrong number of errors. expected=1, got=%d (%q)", len(l.Errors()), l.Errors())
+	}
+	if end := l.Errors()[0].End.String(); end != "line: 5, column: 18" {
+		t.Errorf("end of the error wrong. expected=%q, got=%q", "line: 5, column: 18", end)
+	}
+}
@@ -2,9 +2,10 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/evaluator"
 	"github.com/radlinskii/interpreter/lexer"
 	"github.com/radlinskii/interpreter/object"
@@ -12,28 +13,83 @@ import (
 )
 
 func main() {
+	// commands return the exit code instead of exiting, so their deferred calls run before os.Exit
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "tokens":
+			os.Exit(runTokens(os.Args[2:]))
+		case "doc":
+			os.Exit(runDoc(os.Args[2:]))
+		}
+	}
+
+	os.Exit(runProgram(os.Args[1:]))
+}
+
+// runProgram interprets the program file, it returns the exit code of the program.
+func runProgram(args []string) int {
 	switch {
-	case len(os.Args) == 1:
+	case len(args) == 0:
 		fmt.Println("Please specify the file to be interpreted")
-		os.Exit(1)
-	case len(os.Args) > 2:
+		return 1
+	case len(args) > 1:
 		fmt.Println("Please specify only one file to be interpreted")
-		os.Exit(1)
+		return 1
 	}
 
-	data, err := ioutil.ReadFile(os.Args[1])
+	input, err := openInput(args[0])
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		return 1
 	}
-	input := string(data)
-	l := lexer.New(input)
+	defer input.Close()
+
+	l := newLexer(args[0], input)
 	p := parser.New(l)
 	program := p.ParseProgram()
-	if len(p.Errors()) == 0 {
-		env := object.NewEnvironment()
+	if len(p.Errors()) != 0 {
+		reportErrors(args[0], p.Errors())
+		return 1
+	}
+
+	env := object.NewEnvironment()
+	output, errors := evaluator.EvalProgram(program, env)
+	fmt.Println(output)
+	if len(errors) != 0 {
+		reportErrors(args[0], errors)
+		return 1
+	}
+	return 0
+}
+
+// openInput opens the program file with given name.
+// "-" stands for the standard input, so programs can be piped to the interpreter.
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(name)
+}
+
+// reportErrors renders the diagnostics to the standard error.
+// The program file is read again to show the source lines, the standard input is not.
+func reportErrors(name string, diagnostics []diagnostic.Diagnostic) {
+	var source io.Reader
+	if name != "-" {
+		if file, err := os.Open(name); err == nil {
+			defer file.Close()
+			source = file
+		}
+	}
+
+	diagnostic.Render(os.Stderr, source, diagnostics)
+}
 
-		evaluated := evaluator.EvalProgram(program, env)
-		fmt.Println(evaluated)
+// newLexer creates the Lexer reading the program opened with openInput.
+func newLexer(name string, input io.Reader) *lexer.Lexer {
+	l := lexer.NewReader(input)
+	if name != "-" {
+		l.Filename = name
 	}
+	return l
 }
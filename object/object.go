@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/radlinskii/interpreter/ast"
+	"github.com/radlinskii/interpreter/diagnostic"
+	"github.com/radlinskii/interpreter/token"
 )
 
 // Type represents different object types.
@@ -15,6 +19,8 @@ type Type string
 const (
 	// INTEGER object type
 	INTEGER = "INTEGER"
+	// FLOAT object type
+	FLOAT = "FLOAT"
 	// BOOLEAN object type
 	BOOLEAN = "BOOLEAN"
 	// STRING object type
@@ -35,6 +41,12 @@ const (
 	ARRAY = "ARRAY"
 	// HASH object type
 	HASH = "HASH"
+	// RANGE object type
+	RANGE = "RANGE"
+	// BREAK object type
+	BREAK = "BREAK"
+	// CONTINUE object type
+	CONTINUE = "CONTINUE"
 )
 
 // Object interface is implemented by the objects.
@@ -61,6 +73,26 @@ func (i *Integer) Type() Type {
 	return INTEGER
 }
 
+// Float object.
+type Float struct {
+	Value float64
+}
+
+// Inspect returns value of a float.
+// The value always contains a decimal point or an exponent so it is distinguishable from an integer.
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
+
+// Type returns the float type.
+func (f *Float) Type() Type {
+	return FLOAT
+}
+
 // Boolean object.
 type Boolean struct {
 	Value bool
@@ -132,9 +164,43 @@ func (rv *Return) Type() Type {
 	return RETURN
 }
 
+// Break object stops the loop it is evaluated in.
+type Break struct{}
+
+// Inspect returns break.
+func (b *Break) Inspect() string {
+	return "break"
+}
+
+// Type returns the Break object type.
+func (b *Break) Type() Type {
+	return BREAK
+}
+
+// Continue object skips the rest of the loop's body.
+type Continue struct{}
+
+// Inspect returns continue.
+func (c *Continue) Inspect() string {
+	return "continue"
+}
+
+// Type returns the Continue object type.
+func (c *Continue) Type() Type {
+	return CONTINUE
+}
+
 // Error object.
 type Error struct {
+	Code    diagnostic.Code
 	Message string
+	Pos     token.Position // position of the node which evaluation failed
+	End     token.Position
+}
+
+// Diagnostic returns the error as a diagnostic of the program.
+func (e *Error) Diagnostic() diagnostic.Diagnostic {
+	return diagnostic.Diagnostic{Severity: diagnostic.Error, Code: e.Code, Message: e.Message, Pos: e.Pos, End: e.End}
 }
 
 // Inspect returns error message.
@@ -149,7 +215,8 @@ func (e *Error) Type() Type {
 
 // Function object.
 type Function struct {
-	Parameters []*ast.Identifier
+	Parameters []*ast.PatternElement
+	Rest       *ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -162,6 +229,9 @@ func (f *Function) Inspect() string {
 	for _, p := range f.Parameters {
 		params = append(params, p.String())
 	}
+	if f.Rest != nil {
+		params = append(params, "..."+f.Rest.String())
+	}
 
 	out.WriteString("fun(")
 	out.WriteString(strings.Join(params, ", "))
@@ -178,14 +248,15 @@ func (f *Function) Type() Type {
 
 // Environment is a map of known objects.
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store   map[string]Object
+	mutable map[string]bool // names of the variables declared with "let"
+	outer   *Environment
 }
 
 // NewEnvironment returns new Environment instance
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	return &Environment{store: s, mutable: make(map[string]bool), outer: nil}
 }
 
 // NewEnclosedEnvironment returns new Environment instance
@@ -218,6 +289,29 @@ func (e *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// SetMutable puts the value of given key in Enviroment's map, the key can be later updated with Assign.
+func (e *Environment) SetMutable(name string, val Object) Object {
+	e.mutable[name] = true
+	return e.Set(name, val)
+}
+
+// Assign updates the value of given key in the Environment that declared it.
+// It returns false as found if the key is not declared, and false as mutable if it wasn't set with SetMutable.
+// The value is updated only if the key is found and mutable.
+func (e *Environment) Assign(name string, val Object) (found, mutable bool) {
+	if _, ok := e.store[name]; ok {
+		if !e.mutable[name] {
+			return true, false
+		}
+		e.store[name] = val
+		return true, true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return false, false
+}
+
 // Builtin is a wrapper over built-in function.
 type Builtin struct {
 	Fn BuiltinFunction
@@ -288,6 +382,17 @@ func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+// HashKey returns HashKey created from a Float.
+func (f *Float) HashKey() HashKey {
+	value := f.Value
+	if value == 0 {
+		// -0.0 and 0.0 are equal, so they have to be the same key
+		value = 0
+	}
+
+	return HashKey{Type: f.Type(), Value: math.Float64bits(value)}
+}
+
 // HashKey returns HashKey created from a String.
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
@@ -327,3 +432,49 @@ func (h *Hash) Inspect() string {
 
 	return out.String()
 }
+
+// Range represents integers from Start up to End, End excluded, increasing by Step.
+// The integers are not stored, they are generated while iterating over the Range.
+type Range struct {
+	Start int64
+	End   int64
+	Step  int64 // never zero, negative if the range is decreasing
+}
+
+// Type returns the Range object type.
+func (r *Range) Type() Type {
+	return RANGE
+}
+
+// Inspect returns the Range object image, e.g. "range(0, 10)".
+func (r *Range) Inspect() string {
+	if r.Step == 1 {
+		return fmt.Sprintf("range(%d, %d)", r.Start, r.End)
+	}
+	return fmt.Sprintf("range(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+
+// Len returns the number of integers in the Range.
+// It's computed on unsigned integers, so ranges spanning over the whole int64 don't overflow.
+func (r *Range) Len() uint64 {
+	var span, step uint64
+	switch {
+	case r.Step > 0 && r.Start < r.End:
+		span, step = uint64(r.End-r.Start), uint64(r.Step)
+	case r.Step < 0 && r.Start > r.End:
+		span, step = uint64(r.Start-r.End), -uint64(r.Step)
+	default:
+		return 0
+	}
+
+	length := span / step
+	if span%step != 0 {
+		length++
+	}
+	return length
+}
+
+// At returns the i-th integer of the Range.
+func (r *Range) At(i uint64) int64 {
+	return r.Start + int64(i*uint64(r.Step))
+}
@@ -1,6 +1,9 @@
 package object
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -28,3 +31,65 @@ func TestStringHashKey(t *testing.T) {
 		t.Errorf("strings with different content have same hash keys")
 	}
 }
+
+func TestFloatHashKey(t *testing.T) {
+	tests := []struct {
+		left, right float64
+		equal       bool
+	}{
+		{1.5, 1.5, true},
+		{0, math.Copysign(0, -1), true},
+		{1.5, 2.5, false},
+		{-1.5, 1.5, false},
+	}
+
+	for _, tt := range tests {
+		left, right := &Float{Value: tt.left}, &Float{Value: tt.right}
+		if (left.HashKey() == right.HashKey()) != tt.equal {
+			t.Errorf("wrong hash key equality of %s and %s. expected=%t", left.Inspect(), right.Inspect(), tt.equal)
+		}
+	}
+
+	if (&Float{Value: 1}).HashKey() == (&Integer{Value: 1}).HashKey() {
+		t.Errorf("float and integer have same hash keys")
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{3.14, "3.14"},
+		{3, "3.0"},
+		{-0.5, "-0.5"},
+		{1e21, "1e+21"},
+	}
+
+	for _, tt := range tests {
+		f := &Float{Value: tt.value}
+		if f.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect result. expected=%q, got=%q", tt.expected, f.Inspect())
+		}
+	}
+}
+
+func TestRangeLen(t *testing.T) {
+	tests := []struct {
+		r        *Range
+		expected uint64
+	}{
+		{&Range{Start: 0, End: 10, Step: 1}, 10},
+		{&Range{Start: 0, End: 10, Step: 3}, 4},
+		{&Range{Start: 10, End: 0, Step: -3}, 4},
+		{&Range{Start: 10, End: 0, Step: 1}, 0},
+		{&Range{Start: 5, End: 5, Step: 1}, 0},
+		{&Range{Start: -9223372036854775808, End: 9223372036854775807, Step: 9223372036854775807}, 3},
+	}
+
+	for _, tt := range tests {
+		if tt.r.Len() != tt.expected {
+			t.Errorf("wrong length of %s. expected=%d, got=%d", tt.r.Inspect(), tt.expected, tt.r.Len())
+		}
+	}
+}
@@ -2,10 +2,12 @@ package parser
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
 	"github.com/radlinskii/interpreter/ast"
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/lexer"
 	"github.com/radlinskii/interpreter/token"
 )
@@ -14,26 +16,37 @@ const (
 	_ int = iota
 	// LOWEST == 1 default precedence
 	LOWEST
-	// EQUALS == 2 precedence for operators [==,!=]
+	// PIPE == 2 precedence for operator [|>]
+	PIPE
+	// OR == 3 precedence for operator [||]
+	OR
+	// AND == 4 precedence for operator [&&]
+	AND
+	// EQUALS == 5 precedence for operators [==,!=]
 	EQUALS
-	// LESSGREATER == 3 precedence for operators [>,<,>=,<=]
+	// LESSGREATER == 6 precedence for operators [>,<,>=,<=]
 	LESSGREATER
-	// SUM == 4 precedence for operators [+,"infixed" -]
+	// SUM == 7 precedence for operators [+,"infixed" -,|,^]
 	SUM
-	// PRODUCT == 5 precedence for operators [*,/]
+	// PRODUCT == 8 precedence for operators [*,/,%,&,<<,>>]
 	PRODUCT
-	// PREFIX == 6 precedence for operators ["prefixed" -,!]
+	// PREFIX == 9 precedence for operators ["prefixed" -,!,~]
 	PREFIX
-	// CALL == 7 precedence for operator (
+	// POWER == 10 precedence for right-associative operator [**]
+	POWER
+	// CALL == 11 precedence for operator (
 	CALL
-	// INDEX == 8 precedence for "[x]" opertor
+	// INDEX == 12 precedence for "[x]" and "." opertors
 	INDEX
 )
 
 // list of built-in functions defined in evaluator/builtins.go
-var builtins = map[string]bool{"len": true, "print": true, "first": true, "last": true, "rest": true}
+var builtins = map[string]bool{"len": true, "print": true, "first": true, "last": true, "rest": true, "int": true, "float": true, "range": true}
 
 var precedences = map[token.Type]int{
+	token.PIPE:     PIPE,
+	token.OR:       OR,
+	token.AND:      AND,
 	token.EQ:       EQUALS,
 	token.NEQ:      EQUALS,
 	token.LTE:      LESSGREATER,
@@ -42,10 +55,18 @@ var precedences = map[token.Type]int{
 	token.GT:       LESSGREATER,
 	token.PLUS:     SUM,
 	token.MINUS:    SUM,
+	token.BIT_OR:   SUM,
+	token.BIT_XOR:  SUM,
 	token.SLASH:    PRODUCT,
 	token.ASTERISK: PRODUCT,
+	token.PERCENT:  PRODUCT,
+	token.BIT_AND:  PRODUCT,
+	token.SHL:      PRODUCT,
+	token.SHR:      PRODUCT,
+	token.POWER:    POWER,
 	token.LPAREN:   CALL,
 	token.LBRACKET: INDEX,
+	token.DOT:      INDEX,
 }
 
 type prefixParseFunc func() ast.Expression
@@ -53,11 +74,31 @@ type infixParseFunc func(ast.Expression) ast.Expression
 
 // Parser structure represents the semantic analyzer.
 type Parser struct {
-	lexer *lexer.Lexer
+	lexer     *lexer.Lexer
+	lookahead []token.Token // tokens read from the lexer ahead of peekToken, see peekAhead
 
 	curToken  token.Token
 	peekToken token.Token
-	errors    []string
+	errors    []diagnostic.Diagnostic
+	lexErrors int // number of lexer errors already copied to errors
+
+	// panicking is set after a syntax error, further syntax errors are not reported
+	// until the parser skips to the end of the broken statement
+	panicking  bool
+	depth      int // number of currently open braces
+	blockDepth int // value of depth inside the innermost block being parsed
+	valueDepth int // value of depth inside the innermost if-expression's block, 0 outside of them
+	loopDepth  int // number of loops enclosing the current statement within the current function
+
+	matching   bool           // set while parsing the pattern of a match case, which can contain literals
+	scope      *scope         // names declared in the innermost scope enclosing the current statement
+	guardArrow token.Position // position of the "=>" ending the guard being parsed, it doesn't start an arrow function
+
+	comments        []*ast.CommentGroup
+	commentMap      ast.CommentMap
+	leadComment     *ast.CommentGroup // comment group placed right before the current token
+	lineComment     *ast.CommentGroup // comment group following the current token on the same line
+	peekLeadComment *ast.CommentGroup // comment group placed right before the next token
 
 	prefixParseFuncs map[token.Type]prefixParseFunc
 	infixParseFuncs  map[token.Type]infixParseFunc
@@ -65,8 +106,139 @@ type Parser struct {
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
-	p.peekToken = p.lexer.NextToken()
-	p.checkIfIllegal()
+	if p.curTokenIs(token.LBRACE) {
+		p.depth++
+	} else if p.curTokenIs(token.RBRACE) && p.depth > 0 {
+		p.depth--
+	}
+	p.leadComment = p.peekLeadComment
+	p.lineComment = nil
+	p.peekLeadComment = nil
+
+	p.peekToken = p.readToken()
+	if p.peekToken.Type == token.COMMENT {
+		p.consumeComments()
+	}
+	p.collectLexerErrors()
+}
+
+// Returns next token from the lexer.
+// The lexer has already reported illegal tokens, they are skipped so parsing can go on.
+// Skipping a token is likely to break the statement, so the parser enters the panic mode to avoid reporting errors caused by it.
+func (p *Parser) readToken() token.Token {
+	tok := p.lexToken()
+	for tok.Type == token.ILLEGAL {
+		p.panicking = true
+		tok = p.lexToken()
+	}
+	return tok
+}
+
+// Returns the next token read ahead, or the next token from the lexer.
+func (p *Parser) lexToken() token.Token {
+	if len(p.lookahead) > 0 {
+		tok := p.lookahead[0]
+		p.lookahead = p.lookahead[1:]
+		return tok
+	}
+	return p.lexer.NextToken()
+}
+
+// Returns the i-th token following peekToken without consuming it.
+// The returned tokens may be comments or illegal tokens, which nextToken skips.
+func (p *Parser) peekAhead(i int) token.Token {
+	for len(p.lookahead) <= i {
+		p.lookahead = append(p.lookahead, p.lexer.NextToken())
+	}
+	return p.lookahead[i]
+}
+
+// Records an error found at given span.
+func (p *Parser) error(code diagnostic.Code, pos, end token.Position, format string, a ...interface{}) {
+	p.errors = append(p.errors, diagnostic.Diagnostic{
+		Severity: diagnostic.Error,
+		Code:     code,
+		Message:  fmt.Sprintf(format, a...),
+		Pos:      pos,
+		End:      end,
+	})
+}
+
+// Adds a hint to the last recorded error.
+func (p *Parser) hint(hint string) {
+	last := &p.errors[len(p.errors)-1]
+	last.Hints = append(last.Hints, hint)
+}
+
+// syntaxError records the error unless it's a consequence of the previous one,
+// and puts the parser in the panic mode.
+// Returns false if the error was not recorded.
+func (p *Parser) syntaxError(code diagnostic.Code, pos, end token.Position, format string, a ...interface{}) bool {
+	recorded := !p.panicking
+	if recorded {
+		p.error(code, pos, end, format, a...)
+	}
+	p.panicking = true
+
+	return recorded
+}
+
+// synchronize leaves the panic mode skipping the rest of the broken statement.
+// It stops at the semicolon ending the statement, before the brace closing the block the statement belongs to,
+// or before a keyword starting the next statement.
+func (p *Parser) synchronize() {
+	// the statement might have consumed the brace closing its block
+	for !p.peekTokenIs(token.EOF) && p.depth >= p.blockDepth {
+		if p.depth == p.blockDepth {
+			if p.curTokenIs(token.SEMICOLON) {
+				break
+			}
+			if p.blockDepth > 0 && p.peekTokenIs(token.RBRACE) {
+				break
+			}
+			if p.peekTokenIs(token.CONST) || p.peekTokenIs(token.LET) || p.peekTokenIs(token.RETURN) || p.peekTokenIs(token.FOR) {
+				break
+			}
+		}
+		p.nextToken()
+	}
+
+	p.panicking = false
+}
+
+// Groups the comments found after the current token.
+// A group starting on the line of the current token becomes its line comment,
+// the last group ending on the line before the next token, or on its line, becomes the lead comment of the next token.
+func (p *Parser) consumeComments() {
+	if p.peekToken.Pos.Line == p.curToken.End.Line {
+		group := p.consumeCommentGroup(0)
+		if p.peekToken.Pos.Line != group.End().Line || p.peekTokenIs(token.EOF) {
+			p.lineComment = group
+		}
+	}
+
+	var group *ast.CommentGroup
+	for p.peekTokenIs(token.COMMENT) {
+		group = p.consumeCommentGroup(1)
+	}
+	if group != nil && group.End().Line+1 >= p.peekToken.Pos.Line {
+		p.peekLeadComment = group
+	}
+}
+
+// Reads the comments separated by at most n line breaks into a group.
+func (p *Parser) consumeCommentGroup(n int) *ast.CommentGroup {
+	group := &ast.CommentGroup{}
+	endLine := p.peekToken.Pos.Line
+
+	for p.peekTokenIs(token.COMMENT) && p.peekToken.Pos.Line <= endLine+n {
+		group.List = append(group.List, &ast.Comment{Token: p.peekToken})
+		endLine = p.peekToken.End.Line
+		p.peekToken = p.readToken()
+	}
+	p.comments = append(p.comments, group)
+
+	return group
 }
 
 func (p *Parser) registerPrefix(tokenType token.Type, fn prefixParseFunc) {
@@ -77,9 +249,17 @@ func (p *Parser) registerInfix(tokenType token.Type, fn infixParseFunc) {
 	p.infixParseFuncs[tokenType] = fn
 }
 
+// scope tracks the names declared in one block, function or loop,
+// so assignments to the ones that can't be reassigned are reported before evaluation.
+type scope struct {
+	outer   *scope
+	mutable map[string]bool // declared names, true for the ones declared with let
+}
+
 // New creates new Parser with given lexical analyzer object.
 func New(l *lexer.Lexer) *Parser {
-	p := &Parser{lexer: l, errors: []string{}}
+	p := &Parser{lexer: l, errors: []diagnostic.Diagnostic{}, commentMap: ast.CommentMap{}}
+	p.openScope()
 
 	// read two tokens so curToken and peekToken are both set
 	p.nextToken()
@@ -91,20 +271,29 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BOOLEAN, p.parseBooleanLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.STRING_HEAD, p.parseInterpolatedString)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
 
-	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerPrefix(token.LPAREN, p.parseParenthesized)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
 
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.BIT_NOT, p.parsePrefixExpression)
 
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
 
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NEQ, p.parseInfixExpression)
 	p.registerInfix(token.LTE, p.parseInfixExpression)
@@ -115,6 +304,13 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
+	p.registerInfix(token.POWER, p.parseInfixExpression)
+	p.registerInfix(token.BIT_AND, p.parseInfixExpression)
+	p.registerInfix(token.BIT_OR, p.parseInfixExpression)
+	p.registerInfix(token.BIT_XOR, p.parseInfixExpression)
+	p.registerInfix(token.SHL, p.parseInfixExpression)
+	p.registerInfix(token.SHR, p.parseInfixExpression)
 
 	return p
 }
@@ -129,36 +325,29 @@ func (p *Parser) ParseProgram() *ast.Program {
 		if stmnt != nil {
 			program.Statements = append(program.Statements, stmnt)
 		}
+		if p.panicking {
+			p.synchronize()
+		}
 		p.nextToken()
 	}
 
-	p.printErrors()
+	program.Comments = p.comments
+	program.CommentMap = p.commentMap
 
 	return program
 }
 
-// checkIfIllegal kills the parser if illegal character was found.
-func (p *Parser) checkIfIllegal() {
-	if p.curToken.Type == token.ILLEGAL {
-		p.errors = append(p.errors, p.curToken.Literal)
-	}
-}
-
-func (p *Parser) printErrors() {
-	if len(p.errors) != 0 {
-		for _, msg := range p.Errors() {
-			if strings.HasPrefix(msg, "FATAL") {
-				fmt.Println(msg)
-				break
-			}
-			fmt.Println("ERROR: " + msg)
-		}
-		fmt.Println("")
+// collectLexerErrors appends errors reported by the lexer since the last call.
+func (p *Parser) collectLexerErrors() {
+	lexErrors := p.lexer.Errors()
+	for _, err := range lexErrors[p.lexErrors:] {
+		p.errors = append(p.errors, err)
 	}
+	p.lexErrors = len(lexErrors)
 }
 
-// Errors returns the errors that occurred during the semantic analysis.
-func (p *Parser) Errors() []string {
+// Errors returns the lexical and syntax errors found in the program.
+func (p *Parser) Errors() []diagnostic.Diagnostic {
 	return p.errors
 }
 
@@ -166,10 +355,11 @@ func (p *Parser) Errors() []string {
 func (p *Parser) parseIdentifier() ast.Expression {
 	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	if p.peekTokenIs(token.ASSIGN) {
-		msg := fmt.Sprintf("cannot reassign constant: %q at line: %d", p.curToken.Literal, p.curToken.LineNumber)
-		p.errors = append(p.errors, msg)
-		p.nextToken()
+	if p.peekTokenIs(token.ARROW) && p.peekToken.Pos != p.guardArrow {
+		// single parameter of arrow function, e.g. x => x * 2
+		p.checkIfOverridesBuiltin()
+		fl := &ast.FunctionLiteral{Token: p.curToken, Parameters: []*ast.PatternElement{{Target: ident}}}
+		return p.parseArrowFunctionBody(fl)
 	}
 
 	return ident
@@ -177,15 +367,52 @@ func (p *Parser) parseIdentifier() ast.Expression {
 
 // returns Statement AST node created from current and following tokens.
 func (p *Parser) parseStatement() ast.Statement {
+	leadComment := p.leadComment
+
+	var stmnt ast.Statement
 	switch p.curToken.Type {
 	case token.CONST:
-		return p.parseConstStatement()
+		stmnt = p.parseConstStatement()
+	case token.LET:
+		stmnt = p.parseLetStatement()
 	case token.IF:
-		return p.parseIfStatement()
+		if p.valueDepth > 0 && p.valueDepth == p.blockDepth && p.hasElse() {
+			stmnt = p.parseNestedIfExpression()
+		} else {
+			stmnt = p.parseIfStatement()
+		}
 	case token.RETURN:
-		return p.parseReturnStatement()
+		stmnt = p.parseReturnStatement()
+	case token.FOR:
+		stmnt = p.parseForStatement()
+	case token.BREAK, token.CONTINUE:
+		stmnt = p.parseBranchStatement()
+	case token.IDENT:
+		if p.peekTokenIs(token.ASSIGN) {
+			stmnt = p.parseAssignStatement()
+		} else {
+			stmnt = p.parseExpressionStatement()
+		}
 	default:
-		return p.parseExpressionStatement()
+		stmnt = p.parseExpressionStatement()
+	}
+
+	if stmnt != nil {
+		p.attachComments(stmnt, leadComment)
+	}
+
+	return stmnt
+}
+
+// Attaches the comment group placed before the statement
+// and the one following the statement's last token on the same line.
+func (p *Parser) attachComments(stmnt ast.Statement, leadComment *ast.CommentGroup) {
+	if leadComment != nil {
+		p.commentMap[stmnt] = append(p.commentMap[stmnt], leadComment)
+	}
+	if p.lineComment != nil {
+		p.commentMap[stmnt] = append(p.commentMap[stmnt], p.lineComment)
+		p.lineComment = nil
 	}
 }
 
@@ -212,28 +439,132 @@ func (p *Parser) expectPeek(t token.Type) bool {
 
 // creates an error and adds it to the parser errors list
 func (p *Parser) peekError(t token.Type) {
-	msg := fmt.Sprintf("unexpected token: %q (expected: %q) at line: %d", p.peekToken.Type, t, p.lexer.RowNum)
-	p.errors = append(p.errors, msg)
+	p.syntaxError(diagnostic.UnexpectedToken, p.peekToken.Pos, p.peekToken.End, "unexpected token: %q (expected: %q)", p.peekToken.Type, t)
 }
 
 func (p *Parser) checkIfOverridesBuiltin() {
 	if _, ok := builtins[p.curToken.Literal]; ok {
-		msg := fmt.Sprintf("cannot override built-in function: %q at line: %d", p.curToken.Literal, p.curToken.LineNumber)
-		p.errors = append(p.errors, msg)
+		p.error(diagnostic.BuiltinOverride, p.curToken.Pos, p.curToken.End, "cannot override built-in function: %q", p.curToken.Literal)
+		p.hint("choose a different name")
+	}
+}
+
+func (p *Parser) openScope() {
+	p.scope = &scope{outer: p.scope, mutable: map[string]bool{}}
+}
+
+func (p *Parser) closeScope() {
+	p.scope = p.scope.outer
+}
+
+func (p *Parser) declare(name *ast.Identifier, mutable bool) {
+	p.scope.mutable[name.Value] = mutable
+}
+
+// Declares every name bound by the pattern, they can't be reassigned.
+func (p *Parser) declarePattern(pattern ast.Pattern) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		p.declare(pattern, false)
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			p.declarePattern(element.Target)
+		}
+		if pattern.Rest != nil {
+			p.declare(pattern.Rest, false)
+		}
+	case *ast.HashPattern:
+		for _, property := range pattern.Properties {
+			p.declarePattern(property.Element.Target)
+		}
+	}
+}
+
+// Declares the parameters of the function in a new scope, the caller closes it after parsing the body.
+func (p *Parser) openFunctionScope(fl *ast.FunctionLiteral) {
+	p.openScope()
+	for _, param := range fl.Parameters {
+		p.declarePattern(param.Target)
+	}
+	if fl.Rest != nil {
+		p.declare(fl.Rest, false)
+	}
+}
+
+// Reports the assignment to a name declared in one of the enclosing scopes as not mutable.
+// Names not declared yet are checked during evaluation, they can be declared before the assignment is evaluated.
+func (p *Parser) checkAssignment(name *ast.Identifier) {
+	for s := p.scope; s != nil; s = s.outer {
+		if mutable, ok := s.mutable[name.Value]; ok {
+			if !mutable {
+				p.error(diagnostic.ConstantAssignment, name.Pos(), name.End(), "cannot reassign constant: %q", name.Value)
+				p.hint("declare it with let to make it mutable")
+			}
+			return
+		}
 	}
 }
 
 func (p *Parser) semicolonError() {
 	if p.curToken.Type != token.SEMICOLON {
-		msg := fmt.Sprintf("expected semicolon at line: %d", p.curToken.LineNumber)
-		p.errors = append(p.errors, msg)
+		if p.syntaxError(diagnostic.MissingSemicolon, p.curToken.End, p.curToken.End, "expected semicolon") {
+			p.hint(`add ";" at the end of the statement`)
+		}
 	}
 }
 
-// parses production of var statement --> "var" <ident> "=" <expression> ";"
+// parses production of const statement --> "const" (<ident> | <array pattern> | <hash pattern>) "=" <expression> ";"
 func (p *Parser) parseConstStatement() ast.Statement {
 	stmnt := &ast.ConstStatement{Token: p.curToken}
 
+	if p.leadComment != nil && p.leadComment.List[len(p.leadComment.List)-1].IsDoc() {
+		stmnt.Doc = p.leadComment
+	}
+
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		stmnt.Pattern = p.parsePattern()
+		if stmnt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		p.checkIfOverridesBuiltin()
+
+		stmnt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+
+	p.nextToken()
+
+	stmnt.Value = p.parseExpression(LOWEST)
+
+	if stmnt.Pattern != nil {
+		p.declarePattern(stmnt.Pattern)
+	} else {
+		p.declare(stmnt.Name, false)
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		stmnt.Semicolon = p.curToken.Pos
+	} else {
+		p.semicolonError()
+	}
+
+	return stmnt
+}
+
+// parses production of let statement --> "let" <ident> "=" <expression> ";"
+func (p *Parser) parseLetStatement() ast.Statement {
+	stmnt := &ast.LetStatement{Token: p.curToken}
+
 	if !p.expectPeek(token.IDENT) {
 		return nil
 	}
@@ -250,8 +581,33 @@ func (p *Parser) parseConstStatement() ast.Statement {
 
 	stmnt.Value = p.parseExpression(LOWEST)
 
+	p.declare(stmnt.Name, true)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		stmnt.Semicolon = p.curToken.Pos
+	} else {
+		p.semicolonError()
+	}
+
+	return stmnt
+}
+
+// parses production of assign statement --> <ident> "=" <expression> ";"
+func (p *Parser) parseAssignStatement() ast.Statement {
+	name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	p.checkAssignment(name)
+
+	p.nextToken()
+	stmnt := &ast.AssignStatement{Token: p.curToken, Name: name}
+
+	p.nextToken()
+
+	stmnt.Value = p.parseExpression(LOWEST)
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
+		stmnt.Semicolon = p.curToken.Pos
 	} else {
 		p.semicolonError()
 	}
@@ -265,6 +621,7 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	p.nextToken()
 	if p.curTokenIs(token.SEMICOLON) {
+		stmnt.Semicolon = p.curToken.Pos
 		return stmnt
 	}
 
@@ -272,6 +629,69 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
+		stmnt.Semicolon = p.curToken.Pos
+	} else {
+		p.semicolonError()
+	}
+
+	return stmnt
+}
+
+// parses production of for statement --> "for" "(" <ident> "in" <expression> ")" <block statement>
+func (p *Parser) parseForStatement() ast.Statement {
+	stmnt := &ast.ForStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	p.checkIfOverridesBuiltin()
+
+	stmnt.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmnt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	p.openScope()
+	p.declare(stmnt.Variable, false)
+	p.loopDepth++
+	stmnt.Body = p.parseBlockStatement()
+	p.loopDepth--
+	p.closeScope()
+
+	return stmnt
+}
+
+// parses production of break and continue statements --> ("break" | "continue") ";"
+func (p *Parser) parseBranchStatement() ast.Statement {
+	stmnt := &ast.BranchStatement{Token: p.curToken}
+
+	if p.loopDepth == 0 {
+		p.error(diagnostic.InvalidBranch, p.curToken.Pos, p.curToken.End, "%s statement not permitted outside loop", p.curToken.Literal)
+		if p.valueDepth > 0 {
+			p.hint("the loop can't be left from inside of an if-expression")
+		}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		stmnt.Semicolon = p.curToken.Pos
 	} else {
 		p.semicolonError()
 	}
@@ -289,13 +709,20 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
-	} else {
+		stmnt.Semicolon = p.curToken.Pos
+	} else if !p.isValueBlockEnd() {
 		p.semicolonError()
 	}
 
 	return stmnt
 }
 
+// Checks if the next token closes the if-expression's block the current statement belongs to,
+// the semicolon after the last expression of such block can be omitted.
+func (p *Parser) isValueBlockEnd() bool {
+	return p.peekTokenIs(token.RBRACE) && p.valueDepth > 0 && p.valueDepth == p.blockDepth
+}
+
 // Checks precedence of current token,
 // if not defined in the precedence map returns lowest precedence.
 func (p *Parser) curPrecedence() int {
@@ -314,6 +741,62 @@ func (p *Parser) peekPrecedence() int {
 	return LOWEST
 }
 
+// Parses either the grouped expression or the arrow function with parameters in parenthesis.
+func (p *Parser) parseParenthesized() ast.Expression {
+	if p.isArrowFunction() {
+		fl := &ast.FunctionLiteral{Token: p.curToken}
+		fl.Parameters, fl.Rest = p.parseFunctionParameters()
+		return p.parseArrowFunctionBody(fl)
+	}
+	return p.parseGroupedExpression()
+}
+
+// Checks if the parenthesis starting at the current token are followed by "=>".
+// It reads the tokens up to the closing parenthesis ahead.
+func (p *Parser) isArrowFunction() bool {
+	depth := 1
+	tok := p.peekToken
+	for i := 0; tok.Type != token.EOF; i++ {
+		if tok.Type == token.LPAREN {
+			depth++
+		} else if tok.Type == token.RPAREN {
+			depth--
+		}
+		if depth == 0 {
+			next := p.peekAhead(i)
+			for j := i + 1; next.Type == token.COMMENT || next.Type == token.ILLEGAL; j++ {
+				next = p.peekAhead(j)
+			}
+			return next.Type == token.ARROW && next.Pos != p.guardArrow
+		}
+		tok = p.peekAhead(i)
+	}
+	return false
+}
+
+// parses production of arrow function body --> "=>" <expression>
+// The parameters have been parsed, the current token is the last token before "=>".
+func (p *Parser) parseArrowFunctionBody(fl *ast.FunctionLiteral) ast.Expression {
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+	fl.Arrow = p.curToken.Pos
+	arrow := p.curToken
+
+	p.nextToken()
+	p.openFunctionScope(fl)
+	value := p.parseExpression(LOWEST)
+	p.closeScope()
+	if value == nil {
+		return nil
+	}
+
+	returnStmnt := &ast.ReturnStatement{Token: arrow, ReturnValue: value}
+	fl.Body = &ast.BlockStatement{Token: arrow, Statements: []ast.Statement{returnStmnt}}
+
+	return fl
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 
@@ -349,6 +832,17 @@ func (p *Parser) parseIfStatement() ast.Statement {
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
 
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			ifToken := p.curToken
+			nested := p.parseIfStatement()
+			if nested == nil {
+				return nil
+			}
+			stmnt.Alternative = p.elseIfBlock(ifToken, nested)
+			return stmnt
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -359,19 +853,145 @@ func (p *Parser) parseIfStatement() ast.Statement {
 	return stmnt
 }
 
+// parses production of if expression --> "if" "(" <expression> ")" <value block> "else" (<value block> | <if expression>)
+func (p *Parser) parseIfExpression() ast.Expression {
+	exp := &ast.IfExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Consequence = p.parseValueBlock()
+
+	if !p.peekTokenIs(token.ELSE) {
+		if p.syntaxError(diagnostic.UnexpectedToken, p.peekToken.Pos, p.peekToken.End, "unexpected token: %q (expected: %q)", p.peekToken.Type, token.ELSE) {
+			p.hint("if-expression needs the else branch to have a value when the condition is false")
+		}
+		return nil
+	}
+	p.nextToken()
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		ifToken := p.curToken
+		nested := p.parseIfExpression()
+		if nested == nil {
+			return nil
+		}
+		exp.Alternative = p.elseIfBlock(ifToken, &ast.ExpressionStatement{Token: ifToken, Expression: nested})
+		return exp
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Alternative = p.parseValueBlock()
+
+	return exp
+}
+
+// Parses the if with the else branch placed in the block of an if-expression as an if-expression,
+// so it can be the value of the block. Like the if statement it doesn't need to be followed by a semicolon.
+func (p *Parser) parseNestedIfExpression() ast.Statement {
+	stmnt := &ast.ExpressionStatement{Token: p.curToken}
+
+	stmnt.Expression = p.parseIfExpression()
+	if stmnt.Expression == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+		stmnt.Semicolon = p.curToken.Pos
+	}
+
+	return stmnt
+}
+
+// Checks if the if starting at the current token has the else branch,
+// by looking past its condition and consequence without consuming them.
+func (p *Parser) hasElse() bool {
+	depth, groups := 0, 0
+	tok := p.peekToken
+	for i := 0; tok.Type != token.EOF && depth >= 0; i++ {
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE, token.STRING_HEAD:
+			depth++
+		case token.RPAREN, token.RBRACKET, token.RBRACE, token.STRING_TAIL:
+			depth--
+			if depth == 0 {
+				groups++
+				if groups == 2 {
+					return p.peekAhead(i).Type == token.ELSE
+				}
+			}
+		}
+		tok = p.peekAhead(i)
+	}
+	return false
+}
+
+// Parses the block of an if-expression, the last expression in it doesn't need to be followed by a semicolon.
+// The block has to produce a value, so it can't break the loops enclosing the if-expression.
+func (p *Parser) parseValueBlock() *ast.BlockStatement {
+	outerValueDepth, outerLoopDepth := p.valueDepth, p.loopDepth
+	p.valueDepth, p.loopDepth = p.depth, 0
+	defer func() { p.valueDepth, p.loopDepth = outerValueDepth, outerLoopDepth }()
+
+	return p.parseBlockStatement()
+}
+
+// Wraps the if following "else" in a block, so it can be the alternative of the outer if.
+// The current token is the "}" closing the nested if's last block.
+func (p *Parser) elseIfBlock(ifToken token.Token, nested ast.Statement) *ast.BlockStatement {
+	return &ast.BlockStatement{Token: ifToken, Statements: []ast.Statement{nested}, Rbrace: p.curToken.Pos}
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
 
+	outerBlockDepth := p.blockDepth
+	p.blockDepth = p.depth
+	p.openScope()
+	defer func() {
+		p.blockDepth = outerBlockDepth
+		p.closeScope()
+	}()
+
 	p.nextToken()
 
 	for !p.curTokenIs(token.RBRACE) {
+		if p.curTokenIs(token.EOF) {
+			p.syntaxError(diagnostic.UnexpectedToken, p.curToken.Pos, p.curToken.End, "unexpected token: %q (expected: %q)", p.curToken.Type, token.RBRACE)
+			return block
+		}
+
 		stmnt := p.parseStatement()
 		if stmnt != nil {
 			block.Statements = append(block.Statements, stmnt)
 		}
+		if p.panicking {
+			p.synchronize()
+		}
+		if p.depth < p.blockDepth {
+			break
+		}
 		p.nextToken()
 	}
+	block.Rbrace = p.curToken.Pos
 
 	return block
 }
@@ -403,8 +1023,12 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 // Returns a error message if wrong operator was used as prefix operator. e.g. in "*5;" statement.
 func (p *Parser) noPrefixParseFuncError(t token.Token) {
-	msg := fmt.Sprintf("unexpected token: %q at line: %d", t.Literal, t.LineNumber)
-	p.errors = append(p.errors, msg)
+	if t.Type == token.STRING_MIDDLE || t.Type == token.STRING_TAIL {
+		// the token starts with the "}" closing the interpolation
+		p.syntaxError(diagnostic.UnexpectedToken, t.Pos, t.End, "string interpolation closed with %q before the expression ended", "}")
+		return
+	}
+	p.syntaxError(diagnostic.UnexpectedToken, t.Pos, t.End, "unexpected token: %q", t.Literal)
 }
 
 // Creates a PrefixExpression with current token as prefix operator
@@ -433,6 +1057,10 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.curPrecedence()
+	// lowering the precedence of the right side makes the operator right-associative, e.g. 2 ** 3 ** 2 == 2 ** (3 ** 2)
+	if p.curTokenIs(token.POWER) {
+		precedence--
+	}
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 
@@ -440,13 +1068,38 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 }
 
 // Parses integer tokens into the IntegerLiterals AST nodes.
+// Base prefixes (0x, 0b, 0o) and underscores separating digits are handled by strconv.
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse: %q as integer at line: %d", p.curToken.Literal, p.curToken.LineNumber)
-		p.errors = append(p.errors, msg)
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			p.error(diagnostic.InvalidNumber, p.curToken.Pos, p.curToken.End, "integer literal %q overflows 64-bit integer (max: %d)", p.curToken.Literal, int64(math.MaxInt64))
+			p.hint("use a float literal for larger numbers")
+		} else {
+			p.error(diagnostic.InvalidNumber, p.curToken.Pos, p.curToken.End, "could not parse: %q as integer", p.curToken.Literal)
+		}
+
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+// Parses floating-point number tokens into the FloatLiteral AST nodes.
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+
+	value, err := strconv.ParseFloat(strings.Replace(p.curToken.Literal, "_", "", -1), 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			p.error(diagnostic.InvalidNumber, p.curToken.Pos, p.curToken.End, "float literal %q overflows 64-bit float (max: %g)", p.curToken.Literal, math.MaxFloat64)
+		} else {
+			p.error(diagnostic.InvalidNumber, p.curToken.Pos, p.curToken.End, "could not parse: %q as float", p.curToken.Literal)
+		}
 
 		return nil
 	}
@@ -465,6 +1118,33 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+// parses production of interpolated string --> <string head> <expression> {<string middle> <expression>} <string tail>
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken}
+	str.Parts = append(str.Parts, &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal})
+
+	for {
+		if p.peekTokenIs(token.STRING_MIDDLE) || p.peekTokenIs(token.STRING_TAIL) {
+			p.syntaxError(diagnostic.UnexpectedToken, p.peekToken.Pos, p.peekToken.End, "expected expression in string interpolation")
+			return nil
+		}
+		p.nextToken()
+		str.Parts = append(str.Parts, p.parseExpression(LOWEST))
+
+		if !p.peekTokenIs(token.STRING_MIDDLE) && !p.peekTokenIs(token.STRING_TAIL) {
+			// the "}" closing the interpolation is a part of the following token
+			p.peekError(token.RBRACE)
+			return nil
+		}
+		p.nextToken()
+		str.Parts = append(str.Parts, &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal})
+
+		if p.curTokenIs(token.STRING_TAIL) {
+			return str
+		}
+	}
+}
+
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	fl := &ast.FunctionLiteral{Token: p.curToken}
 
@@ -472,52 +1152,312 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	fl.Parameters = p.parseFunctionParameters()
+	fl.Parameters, fl.Rest = p.parseFunctionParameters()
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	// loops and if-expressions outside the function don't affect its body
+	outerValueDepth, outerLoopDepth := p.valueDepth, p.loopDepth
+	p.valueDepth, p.loopDepth = 0, 0
+	p.openFunctionScope(fl)
 	fl.Body = p.parseBlockStatement()
+	p.closeScope()
+	p.valueDepth, p.loopDepth = outerValueDepth, outerLoopDepth
 
 	return fl
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// parses production of parameters --> "(" {<pattern element> ","} ["..." <ident>] ")"
+// Returns the parameters and the rest parameter, which is nil if the function isn't variadic.
+func (p *Parser) parseFunctionParameters() ([]*ast.PatternElement, *ast.Identifier) {
+	params := []*ast.PatternElement{}
+	var rest *ast.Identifier
 
-	if p.peekTokenIs(token.RPAREN) {
+	for !p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil, nil
+			}
+			rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			// the rest has to be the last parameter, the closing parenthesis is expected next
+			break
+		}
+
+		// names bound by destructuring patterns and the rest parameter can shadow built-in functions, plain parameters can't
+		if p.curTokenIs(token.IDENT) {
+			p.checkIfOverridesBuiltin()
+		}
+
+		param := p.parsePatternElement()
+		if param == nil {
+			return nil, nil
+		}
+		if param.Default == nil && len(params) > 0 && params[len(params)-1].Default != nil {
+			p.error(diagnostic.InvalidParameter, param.Pos(), param.End(), "parameter without default value follows parameter with default value")
+			p.hint("move the parameters with default values to the end, or give a default value to this one")
+		}
+		params = append(params, param)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil, nil
+		}
 	}
 
-	p.nextToken()
+	if !p.expectPeek(token.RPAREN) {
+		return nil, nil
+	}
 
-	p.checkIfOverridesBuiltin()
+	return params, rest
+}
 
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
+// parses production of pattern --> <ident> | <array pattern> | <hash pattern>
+// Patterns of match cases can also be literals. --> <literal pattern>
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	case token.INT, token.FLOAT, token.STRING, token.BOOLEAN, token.MINUS:
+		if p.matching {
+			return p.parseLiteralPattern()
+		}
+	}
 
-	for p.peekTokenIs(token.COMMA) {
+	p.syntaxError(diagnostic.UnexpectedToken, p.curToken.Pos, p.curToken.End, "unexpected token: %q (expected: %q)", p.curToken.Type, token.IDENT)
+	return nil
+}
+
+// parses production of literal pattern --> ["-"] (<int> | <float>) | <string> | <boolean>
+func (p *Parser) parseLiteralPattern() ast.Pattern {
+	if p.curTokenIs(token.MINUS) && !p.peekTokenIs(token.INT) && !p.peekTokenIs(token.FLOAT) {
+		p.peekError(token.INT)
+		return nil
+	}
+
+	value := p.prefixParseFuncs[p.curToken.Type]()
+	if value == nil {
+		return nil
+	}
+
+	return &ast.LiteralPattern{Value: value}
+}
+
+// parses production of pattern element --> <pattern> ["=" <expression>]
+func (p *Parser) parsePatternElement() *ast.PatternElement {
+	target := p.parsePattern()
+	if target == nil {
+		return nil
+	}
+
+	element := &ast.PatternElement{Target: target}
+
+	// patterns of match cases don't have defaults
+	if p.peekTokenIs(token.ASSIGN) && !p.matching {
 		p.nextToken()
 		p.nextToken()
+		element.Default = p.parseExpression(LOWEST)
+	}
 
+	return element
+}
+
+// parses production of array pattern --> "[" {<pattern element> ","} ["..." <ident>] "]"
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken, Elements: []*ast.PatternElement{}}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			// the rest has to be the last element, the closing bracket is expected next
+			break
+		}
+
+		element := p.parsePatternElement()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	pattern.Rbracket = p.curToken.Pos
+
+	return pattern
+}
+
+// parses production of hash pattern --> "{" {(<ident> ["=" <expression>] | <key> ":" <pattern element>) ","} "}"
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken, Properties: []*ast.PatternProperty{}}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		property := &ast.PatternProperty{}
+		switch p.curToken.Type {
+		case token.IDENT:
+			property.Key = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		case token.STRING:
+			property.Key = p.parseStringLiteral()
+		case token.INT:
+			property.Key = p.parseIntegerLiteral()
+			if property.Key == nil {
+				return nil
+			}
+		default:
+			p.syntaxError(diagnostic.UnexpectedToken, p.curToken.Pos, p.curToken.End, "unexpected token: %q (expected: %q)", p.curToken.Type, token.IDENT)
+			return nil
+		}
+
+		if key, ok := property.Key.(*ast.Identifier); ok && !p.peekTokenIs(token.COLON) {
+			// shorthand binding the value to the identifier of the key
+			property.Element = &ast.PatternElement{Target: key}
+			if p.peekTokenIs(token.ASSIGN) && !p.matching {
+				p.nextToken()
+				p.nextToken()
+				property.Element.Default = p.parseExpression(LOWEST)
+			}
+		} else {
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			property.Element = p.parsePatternElement()
+			if property.Element == nil {
+				return nil
+			}
+		}
+		pattern.Properties = append(pattern.Properties, property)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	pattern.Rbrace = p.curToken.Pos
+
+	return pattern
+}
+
+// parses production of match expression --> "match" "(" <expression> ")" "{" {<match case> ","} "}"
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken, Cases: []*ast.MatchCase{}}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		matchCase := p.parseMatchCase()
+		if matchCase == nil {
+			return nil
+		}
+		exp.Cases = append(exp.Cases, matchCase)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	exp.Rbrace = p.curToken.Pos
+
+	return exp
+}
+
+// parses production of match case --> <pattern> ["if" <expression>] "=>" <expression>
+func (p *Parser) parseMatchCase() *ast.MatchCase {
+	if p.curTokenIs(token.IDENT) {
 		p.checkIfOverridesBuiltin()
+	}
 
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, ident)
+	p.matching = true
+	pattern := p.parsePattern()
+	p.matching = false
+	if pattern == nil {
+		return nil
 	}
 
-	if !p.expectPeek(token.RPAREN) {
+	matchCase := &ast.MatchCase{Pattern: pattern}
+
+	p.openScope()
+	p.declarePattern(pattern)
+	defer p.closeScope()
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+
+		outerGuardArrow := p.guardArrow
+		p.guardArrow = p.findGuardArrow()
+		p.nextToken()
+		matchCase.Guard = p.parseExpression(LOWEST)
+		p.guardArrow = outerGuardArrow
+	}
+
+	if !p.expectPeek(token.ARROW) {
 		return nil
 	}
+	p.nextToken()
+	matchCase.Body = p.parseExpression(LOWEST)
 
-	return identifiers
+	return matchCase
+}
+
+// Returns position of the "=>" ending the guard which starts at the peek token.
+// Arrows nested in parentheses, brackets or braces belong to the arrow functions used in the guard.
+func (p *Parser) findGuardArrow() token.Position {
+	depth := 0
+	tok := p.peekToken
+	for i := 0; tok.Type != token.EOF && depth >= 0; i++ {
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE, token.STRING_HEAD:
+			depth++
+		case token.RPAREN, token.RBRACKET, token.RBRACE, token.STRING_TAIL:
+			depth--
+		case token.ARROW:
+			if depth == 0 {
+				return tok.Pos
+			}
+		}
+		tok = p.peekAhead(i)
+	}
+	return token.Position{}
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	exp.Rparen = p.curToken.Pos
 
 	return exp
 }
@@ -531,12 +1471,12 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 	}
 
 	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
+	list = append(list, p.parseListElement())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseListElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -546,9 +1486,27 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 	return list
 }
 
+// parses production of list element --> ["..."] <expression>
+func (p *Parser) parseListElement() ast.Expression {
+	if p.curTokenIs(token.ELLIPSIS) {
+		return p.parseSpreadElement()
+	}
+	return p.parseExpression(LOWEST)
+}
+
+func (p *Parser) parseSpreadElement() ast.Expression {
+	spread := &ast.SpreadElement{Token: p.curToken}
+
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+
+	return spread
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Rbracket = p.curToken.Pos
 
 	return array
 }
@@ -562,6 +1520,19 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
+	exp.Rbracket = p.curToken.Pos
+
+	return exp
+}
+
+// Creates an IndexExpression with the field's name as a string key. --> <expression> "." <ident>
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Right = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 
 	return exp
 }
@@ -572,6 +1543,16 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			hash.Keys = append(hash.Keys, p.parseSpreadElement())
+
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
 		key := p.parseExpression(LOWEST)
 
 		if !p.expectPeek(token.COLON) {
@@ -582,6 +1563,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		value := p.parseExpression(LOWEST)
 
 		hash.Pairs[key] = value
+		hash.Keys = append(hash.Keys, key)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -592,6 +1574,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	if !p.expectPeek(token.RBRACE) {
 		return nil
 	}
+	hash.Rbrace = p.curToken.Pos
 
 	return hash
 }
@@ -2,9 +2,11 @@ package parser
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/radlinskii/interpreter/ast"
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/lexer"
 )
 
@@ -90,6 +92,59 @@ func testConstStatement(t *testing.T, s ast.Statement, name string) bool {
 	return true
 }
 
+func TestLetAndAssignStatements(t *testing.T) {
+	input := `
+	let x = 5;
+	x = x + 1;
+	`
+
+	program := testParsingInput(t, input, 2)
+
+	let, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.LetStatement. got=%T", program.Statements[0])
+	}
+	if !testIdentifier(t, let.Name, "x") || !testLiteralExpression(t, let.Value, 5) {
+		return
+	}
+
+	assign, ok := program.Statements[1].(*ast.AssignStatement)
+	if !ok {
+		t.Fatalf("program.Statements[1] is not *ast.AssignStatement. got=%T", program.Statements[1])
+	}
+	if !testIdentifier(t, assign.Name, "x") || !testInfixExpression(t, assign.Value, "x", "+", 1) {
+		return
+	}
+
+	if assign.String() != "x = (x + 1);" {
+		t.Errorf("assign.String() wrong. got=%q", assign.String())
+	}
+}
+
+func TestDestructuringPatterns(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const [a, b] = arr;", "const [a, b] = arr;"},
+		{"const [a, b = 2, ...others] = arr;", "const [a, b = 2, ...others] = arr;"},
+		{"const [] = arr;", "const [] = arr;"},
+		{"const [...all] = arr;", "const [...all] = arr;"},
+		{"const {name, age} = person;", "const {name, age} = person;"},
+		{`const {name: n = "?", "home town": town, 1: one} = person;`, "const {name: n = ?, home town: town, 1: one} = person;"},
+		{"const {address: {city}, tags: [tag]} = person;", "const {address: {city}, tags: [tag]} = person;"},
+		{"const f = fun([a, b], {c}) { return a; };", "const f = fun([a, b], {c})return a;;"},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -162,6 +217,61 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestIntegerLiteralForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0xFF;", 255},
+		{"0b1010;", 10},
+		{"0o755;", 493},
+		{"1_000_000;", 1000000},
+		{"0x7FFF_FFFF_FFFF_FFFF;", 9223372036854775807},
+	}
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		stmnt := program.Statements[0].(*ast.ExpressionStatement)
+		integer, ok := stmnt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("exp is not *ast.IntegerLiteral. got=%T", stmnt.Expression)
+		}
+
+		if integer.Value != tt.expected {
+			t.Errorf("integer.Value not %d. got=%d", tt.expected, integer.Value)
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"1e-3;", 0.001},
+		{"2.5E+2;", 250},
+		{"1_000.5;", 1000.5},
+	}
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		stmnt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement. got=%q", program.Statements[0])
+		}
+
+		float, ok := stmnt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("exp is not *ast.FloatLiteral. got=%T", stmnt.Expression)
+		}
+
+		if float.Value != tt.expected {
+			t.Errorf("float.Value not %g. got=%g", tt.expected, float.Value)
+		}
+	}
+}
+
 func TestBooleanLiteralExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -258,6 +368,11 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"a + b + c;", "((a + b) + c)"},
 		{"a + b - c;", "((a + b) - c)"},
 		{"a + -b;", "(a + (-b))"},
+		{"a |> f;", "(a |> f)"},
+		{"a |> f |> g(b);", "((a |> f) |> g(b))"},
+		{"a + b |> f(c * d);", "((a + b) |> f((c * d)))"},
+		{"a || b |> f;", "((a || b) |> f)"},
+		{"a |> obj.f(b)[0];", "(a |> ((obj.f)(b)[0]))"},
 		{"a * b + c;", "((a * b) + c)"},
 		{"a + b / c;", "(a + (b / c))"},
 		{"5 > 4 == 2 < 3;", "((5 > 4) == (2 < 3))"},
@@ -272,6 +387,22 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"add(a+b+c*d/f, g);", "add(((a + b) + ((c * d) / f)), g)"},
 		{"a * [1, 2, 3, 4][b*c] * d;", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
 		{"add(a * b[2], b[1], 2 * [1, 2][1]);", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"a || b && c;", "(a || (b && c))"},
+		{"a && b || c && d;", "((a && b) || (c && d))"},
+		{"a || b || c;", "((a || b) || c)"},
+		{"a < b && b != c == d;", "((a < b) && ((b != c) == d))"},
+		{"!a && -b > c;", "((!a) && ((-b) > c))"},
+		{"a % b * c;", "((a % b) * c)"},
+		{"a + b % c;", "(a + (b % c))"},
+		{"a ** b ** c;", "(a ** (b ** c))"},
+		{"-a ** b;", "(-(a ** b))"},
+		{"a ** -b;", "(a ** (-b))"},
+		{"a * b ** c;", "(a * (b ** c))"},
+		{"a | b & c;", "(a | (b & c))"},
+		{"a ^ b | c;", "((a ^ b) | c)"},
+		{"a & 1 == 0;", "((a & 1) == 0)"},
+		{"1 << a + b >> 2;", "((1 << a) + (b >> 2))"},
+		{"~a & b;", "((~a) & b)"},
 	}
 
 	for _, tt := range tests {
@@ -354,6 +485,146 @@ func TestIfElseStatement(t *testing.T) {
 	}
 }
 
+func TestElseIfStatement(t *testing.T) {
+	input := `
+	if (x < y) {
+		x;
+	} else if (x > y) {
+		y;
+	} else {
+		z;
+	}`
+
+	program := testParsingInput(t, input, 1)
+
+	stmnt, ok := program.Statements[0].(*ast.IfStatement)
+	if !ok {
+		t.Fatalf("stmnt is not *ast.IfStatement. got=%T", stmnt)
+	}
+
+	if len(stmnt.Alternative.Statements) != 1 {
+		t.Fatalf("stmnt.Alternative does not contain 1 statement. got=%d", len(stmnt.Alternative.Statements))
+	}
+
+	nested, ok := stmnt.Alternative.Statements[0].(*ast.IfStatement)
+	if !ok {
+		t.Fatalf("stmnt.Alternative.Statements[0] is not *ast.IfStatement. got=%T", stmnt.Alternative.Statements[0])
+	}
+
+	if !testInfixExpression(t, nested.Condition, "x", ">", "y") {
+		return
+	}
+
+	alternative, ok := nested.Alternative.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("nested.Alternative.Statements[0] is not *ast.ExpressionStatement. got=%T", nested.Alternative.Statements[0])
+	}
+
+	if !testIdentifier(t, alternative.Expression, "z") {
+		return
+	}
+}
+
+func TestIfExpression(t *testing.T) {
+	input := `const max = if (x > y) { x } else if (x < y) { y } else { const z = x; z };`
+
+	program := testParsingInput(t, input, 1)
+
+	stmnt := program.Statements[0].(*ast.ConstStatement)
+	exp, ok := stmnt.Value.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmnt.Value is not *ast.IfExpression. got=%T", stmnt.Value)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", ">", "y") {
+		return
+	}
+
+	consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("exp.Consequence.Statements[0] is not *ast.ExpressionStatement. got=%T", exp.Consequence.Statements[0])
+	}
+
+	if !testIdentifier(t, consequence.Expression, "x") {
+		return
+	}
+
+	nestedStmnt, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("exp.Alternative.Statements[0] is not *ast.ExpressionStatement. got=%T", exp.Alternative.Statements[0])
+	}
+
+	nested, ok := nestedStmnt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("nested expression is not *ast.IfExpression. got=%T", nestedStmnt.Expression)
+	}
+
+	if len(nested.Alternative.Statements) != 2 {
+		t.Fatalf("nested.Alternative does not contain 2 statements. got=%d", len(nested.Alternative.Statements))
+	}
+}
+
+func TestNestedIfExpression(t *testing.T) {
+	input := `const x = if (a) { if (b) { 1 } else { 2 } } else { if (c) { 3; } 4 };`
+
+	program := testParsingInput(t, input, 1)
+
+	exp := program.Statements[0].(*ast.ConstStatement).Value.(*ast.IfExpression)
+
+	nestedStmnt, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("exp.Consequence.Statements[0] is not *ast.ExpressionStatement. got=%T", exp.Consequence.Statements[0])
+	}
+
+	if _, ok := nestedStmnt.Expression.(*ast.IfExpression); !ok {
+		t.Fatalf("nested expression is not *ast.IfExpression. got=%T", nestedStmnt.Expression)
+	}
+
+	if len(exp.Alternative.Statements) != 2 {
+		t.Fatalf("exp.Alternative does not contain 2 statements. got=%d", len(exp.Alternative.Statements))
+	}
+
+	if _, ok := exp.Alternative.Statements[0].(*ast.IfStatement); !ok {
+		t.Fatalf("exp.Alternative.Statements[0] is not *ast.IfStatement. got=%T", exp.Alternative.Statements[0])
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	input := `
+	for (x in range(10)) {
+		if (x == 5) { break; }
+		continue;
+	}`
+
+	program := testParsingInput(t, input, 1)
+
+	stmnt, ok := program.Statements[0].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("stmnt is not *ast.ForStatement. got=%T", program.Statements[0])
+	}
+
+	if !testIdentifier(t, stmnt.Variable, "x") {
+		return
+	}
+
+	if stmnt.Iterable.String() != "range(10)" {
+		t.Errorf("stmnt.Iterable is not %q. got=%q", "range(10)", stmnt.Iterable.String())
+	}
+
+	if len(stmnt.Body.Statements) != 2 {
+		t.Fatalf("stmnt.Body does not contain 2 statements. got=%d", len(stmnt.Body.Statements))
+	}
+
+	branch, ok := stmnt.Body.Statements[1].(*ast.BranchStatement)
+	if !ok {
+		t.Fatalf("stmnt.Body.Statements[1] is not *ast.BranchStatement. got=%T", stmnt.Body.Statements[1])
+	}
+
+	if branch.TokenLiteral() != "continue" {
+		t.Errorf("branch.TokenLiteral not 'continue'. got=%q", branch.TokenLiteral())
+	}
+}
+
 func testPrefixExpression(t *testing.T, stmnt ast.Expression, operator string, right interface{}) bool {
 	pe, ok := stmnt.(*ast.PrefixExpression)
 	if !ok {
@@ -491,8 +762,8 @@ func TestFunctionLiteral(t *testing.T) {
 		t.Fatalf("expected 2 function parameters. got=%d", len(function.Parameters))
 	}
 
-	testLiteralExpression(t, function.Parameters[0], "x")
-	testLiteralExpression(t, function.Parameters[1], "y")
+	testLiteralExpression(t, function.Parameters[0].Target.(ast.Expression), "x")
+	testLiteralExpression(t, function.Parameters[1].Target.(ast.Expression), "y")
 
 	if len(function.Body.Statements) != 1 {
 		t.Fatalf("function.Body.Statements length expected to be 1. got=%d", len(function.Body.Statements))
@@ -528,7 +799,182 @@ func TestFunctionParametersParsing(t *testing.T) {
 		}
 
 		for i, ident := range tt.expectedParams {
-			testLiteralExpression(t, function.Parameters[i], ident)
+			testLiteralExpression(t, function.Parameters[i].Target.(ast.Expression), ident)
+		}
+	}
+}
+
+func TestDefaultAndRestParametersParsing(t *testing.T) {
+	tests := []struct {
+		input        string
+		expected     string
+		expectedRest string
+	}{
+		{input: "fun(x, y = 2) {};", expected: "fun(x, y = 2)"},
+		{input: "fun(x = 1, y = x * 2) {};", expected: "fun(x = 1, y = (x * 2))"},
+		{input: "fun(...args) {};", expected: "fun(...args)", expectedRest: "args"},
+		{input: "fun(x, [y, z] = [1, 2], ...args) {};", expected: "fun(x, [y, z] = [1, 2], ...args)", expectedRest: "args"},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		stmnt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmnt.Expression.(*ast.FunctionLiteral)
+
+		if function.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, function.String())
+		}
+
+		if tt.expectedRest == "" {
+			if function.Rest != nil {
+				t.Errorf("function.Rest is not nil. got=%q", function.Rest)
+			}
+		} else if !testIdentifier(t, function.Rest, tt.expectedRest) {
+			return
+		}
+	}
+}
+
+func TestArrowFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x => x * 2;", "(x) => (x * 2)"},
+		{"(x) => x * 2;", "(x) => (x * 2)"},
+		{"() => 42;", "() => 42"},
+		{"(a, b = 1, ...others) => a + b;", "(a, b = 1, ...others) => (a + b)"},
+		{"([a, b], {c}) => a;", "([a, b], {c}) => a"},
+		{"x => y => x + y;", "(x) => (y) => (x + y)"},
+		{"map(xs, (x) => (x + 1) * 2);", "map(xs, (x) => ((x + 1) * 2))"},
+		{"(x) /* comment */ => x;", "(x) => x"},
+		{"(a + (b)) * c;", "((a + b) * c)"},
+		{"((x) => x)(1);", "(x) => x(1)"},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedParts int
+	}{
+		{`"Hello ${name}";`, "Hello ${name}", 3},
+		{`"${a} and ${b + 1}!";`, "${a} and ${(b + 1)}!", 5},
+		{`"${"in${x}ner"}";`, "${in${x}ner}", 3},
+		{`"${ {"a": 1}["a"] }";`, "${({a:1}[a])}", 3},
+		{`"${x => x}";`, "${(x) => x}", 3},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		str, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InterpolatedString)
+		if !ok {
+			t.Fatalf("expression is not ast.InterpolatedString. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if len(str.Parts) != tt.expectedParts {
+			t.Errorf("wrong number of parts. expected=%d, got=%d", tt.expectedParts, len(str.Parts))
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestMemberExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"obj.name;", "(obj.name)"},
+		{"obj.inner.name;", "((obj.inner).name)"},
+		{`obj.greet("Jane");`, "(obj.greet)(Jane)"},
+		{"obj.items[0].len;", "(((obj.items)[0]).len)"},
+		{"-obj.count * 2;", "((-(obj.count)) * 2)"},
+		{"makeObj().name;", "(makeObj().name)"},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := testParsingInput(t, "obj.name;", 1)
+	exp, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("expression is not ast.IndexExpression. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	testIdentifier(t, exp.Left, "obj")
+	testStringLiteral(t, exp.Right, "name")
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedCases int
+	}{
+		{"match (x) { 0 => a, _ => b };", "match(x) {0 => a, _ => b}", 2},
+		{"match (x) { -1 => a, 2.5 => b, \"s\" => c, true => d, };", "match(x) {(-1) => a, 2.5 => b, s => c, true => d}", 4},
+		{"match (x) { [] => a, [0, y] => y, [h, ...t] => t };", "match(x) {[] => a, [0, y] => y, [h, ...t] => t}", 3},
+		{`match (x) { {name, "age": 18, 1: [z]} => name };`, "match(x) {{name, age: 18, 1: [z]} => name}", 1},
+		{"match (x) { n if n > 0 => n, n => -n };", "match(x) {n if (n > 0) => n, n => (-n)}", 2},
+		{"match (x) { n if ok => n };", "match(x) {n if ok => n}", 1},
+		{"match (x) { n if (ok) => n };", "match(x) {n if ok => n}", 1},
+		{"match (x) { n if any(n, (y) => y) => n };", "match(x) {n if any(n, (y) => y) => n}", 1},
+		{"match (x) { f => y => f(y) };", "match(x) {f => (y) => f(y)}", 1},
+		{"match (x) {};", "match(x) {}", 0},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		exp, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+		if !ok {
+			t.Fatalf("expression is not ast.MatchExpression. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if len(exp.Cases) != tt.expectedCases {
+			t.Errorf("wrong number of cases. expected=%d, got=%d", tt.expectedCases, len(exp.Cases))
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestSpreadParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[...a];", "[...a]"},
+		{"[...a, 1, ...b];", "[...a, 1, ...b]"},
+		{"[...a + b];", "[...(a + b)]"},
+		{"add(...args);", "add(...args)"},
+		{"add(1, ...[2, 3]);", "add(1, ...[2, 3])"},
+		{`{...base};`, "{...base}"},
+		{`{...base, "k": 1, ...other,};`, "{...base, k:1, ...other}"},
+		{`{"k": 1, ...base};`, "{k:1, ...base}"},
+	}
+
+	for _, tt := range tests {
+		program := testParsingInput(t, tt.input, 1)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
 		}
 	}
 }
@@ -738,15 +1184,58 @@ func TestParserErrors(t *testing.T) {
 		input            string
 		expectedErrorMsg string
 	}{
-		{input: "$", expectedErrorMsg: "FATAL ERROR: illegal character: \"$\" at line: 1\n\n"},
-		{input: `const foo = "`, expectedErrorMsg: "FATAL ERROR: string literal not terminated at line: 1\n\n"},
-		{input: `const foo = "a string"; /* comment not terminated...`, expectedErrorMsg: "FATAL ERROR: comment not terminated at line: 1\n\n"},
-		{input: `const foo = "a string"`, expectedErrorMsg: "expected semicolon at line: 1"},
-		{input: `foo`, expectedErrorMsg: "expected semicolon at line: 1"},
-		{input: `const print = "a string";`, expectedErrorMsg: `cannot override built-in function: "print" at line: 1`},
-		{input: `const foo "string";`, expectedErrorMsg: `unexpected token: "STRING" (expected: "=") at line: 1`},
-		{input: `=`, expectedErrorMsg: `unexpected token: "=" at line: 1`},
-		{input: `const foo = "a string"; foo = 1234;`, expectedErrorMsg: `cannot reassign constant: "foo" at line: 1`},
+		{input: "$", expectedErrorMsg: `illegal character: "$" at line: 1, column: 1`},
+		{input: `const foo = "`, expectedErrorMsg: "string literal not terminated at line: 1, column: 13"},
+		{input: `const foo = "a string"; /* comment not terminated...`, expectedErrorMsg: "comment not terminated at line: 1, column: 25"},
+		{input: `const foo = "a string"`, expectedErrorMsg: "expected semicolon at line: 1, column: 23"},
+		{input: `foo`, expectedErrorMsg: "expected semicolon at line: 1, column: 4"},
+		{input: `const print = "a string";`, expectedErrorMsg: `cannot override built-in function: "print" at line: 1, column: 7`},
+		{input: `const foo "string";`, expectedErrorMsg: `unexpected token: "STRING" (expected: "=") at line: 1, column: 11`},
+		{input: `=`, expectedErrorMsg: `unexpected token: "=" at line: 1, column: 1`},
+		{input: `9223372036854775808;`, expectedErrorMsg: `integer literal "9223372036854775808" overflows 64-bit integer (max: 9223372036854775807) at line: 1, column: 1`},
+		{input: `const x = 1e400;`, expectedErrorMsg: `float literal "1e400" overflows 64-bit float (max: 1.7976931348623157e+308) at line: 1, column: 11`},
+		{input: `const [a, ...b, c] = arr;`, expectedErrorMsg: `unexpected token: "," (expected: "]") at line: 1, column: 15`},
+		{input: `const [a, 1] = arr;`, expectedErrorMsg: `unexpected token: "INT" (expected: "IDENT") at line: 1, column: 11`},
+		{input: `const {"name"} = person;`, expectedErrorMsg: `unexpected token: "}" (expected: ":") at line: 1, column: 14`},
+		{input: `const f = fun(x, print) { return x; };`, expectedErrorMsg: `cannot override built-in function: "print" at line: 1, column: 18`},
+		{input: `const f = fun(x = 1, y) { return y; };`, expectedErrorMsg: "parameter without default value follows parameter with default value at line: 1, column: 22"},
+		{input: `const f = fun(...xs, y) { return y; };`, expectedErrorMsg: `unexpected token: "," (expected: ")") at line: 1, column: 20`},
+		{input: `const f = (x, 1) => x;`, expectedErrorMsg: `unexpected token: "INT" (expected: "IDENT") at line: 1, column: 15`},
+		{input: `const f = (x) => ;`, expectedErrorMsg: `unexpected token: ";" at line: 1, column: 18`},
+		{input: `const f = len => 1;`, expectedErrorMsg: `cannot override built-in function: "len" at line: 1, column: 11`},
+		{input: `const arr = [...];`, expectedErrorMsg: `unexpected token: "]" at line: 1, column: 17`},
+		{input: `const hash = {..."k": 1};`, expectedErrorMsg: `unexpected token: ":" (expected: ",") at line: 1, column: 21`},
+		{input: `const s = "${a b}";`, expectedErrorMsg: `unexpected token: "IDENT" (expected: "}") at line: 1, column: 16`},
+		{input: `const s = "${}";`, expectedErrorMsg: `expected expression in string interpolation at line: 1, column: 14`},
+		{input: `const s = "${ 1 + }";`, expectedErrorMsg: `string interpolation closed with "}" before the expression ended at line: 1, column: 19`},
+		{input: `obj.;`, expectedErrorMsg: `unexpected token: ";" (expected: "IDENT") at line: 1, column: 5`},
+		{input: `obj."name";`, expectedErrorMsg: `unexpected token: "STRING" (expected: "IDENT") at line: 1, column: 5`},
+		{input: `match x { _ => 1 };`, expectedErrorMsg: `unexpected token: "IDENT" (expected: "(") at line: 1, column: 7`},
+		{input: `match (x) { 1 + 1 => 1 };`, expectedErrorMsg: `unexpected token: "+" (expected: "=>") at line: 1, column: 15`},
+		{input: `match (x) { -y => 1 };`, expectedErrorMsg: `unexpected token: "IDENT" (expected: "INT") at line: 1, column: 14`},
+		{input: `match (x) { [a = 1] => a };`, expectedErrorMsg: `unexpected token: "=" (expected: ",") at line: 1, column: 16`},
+		{input: `match (x) { _ => 1 _ => 2 };`, expectedErrorMsg: `unexpected token: "IDENT" (expected: ",") at line: 1, column: 20`},
+		{input: `match (x) { len => 1 };`, expectedErrorMsg: `cannot override built-in function: "len" at line: 1, column: 13`},
+		{input: `const [1] = arr;`, expectedErrorMsg: `unexpected token: "INT" (expected: "IDENT") at line: 1, column: 8`},
+		{input: `let foo;`, expectedErrorMsg: `unexpected token: ";" (expected: "=") at line: 1, column: 8`},
+		{input: `let first = 1;`, expectedErrorMsg: `cannot override built-in function: "first" at line: 1, column: 5`},
+		{input: `const a = 1; a = 2;`, expectedErrorMsg: `cannot reassign constant: "a" at line: 1, column: 14`},
+		{input: `let a = 1; if (true) { const a = 2; a = 3; }`, expectedErrorMsg: `cannot reassign constant: "a" at line: 1, column: 37`},
+		{input: `const f = fun(x) { x = 2; return x; };`, expectedErrorMsg: `cannot reassign constant: "x" at line: 1, column: 20`},
+		{input: `const f = fun(...xs) { return fun() { xs = []; }; };`, expectedErrorMsg: `cannot reassign constant: "xs" at line: 1, column: 39`},
+		{input: `for (x in [1]) { x = 2; }`, expectedErrorMsg: `cannot reassign constant: "x" at line: 1, column: 18`},
+		{input: `const [a, {b}] = [1, {"b": 2}]; b = 3;`, expectedErrorMsg: `cannot reassign constant: "b" at line: 1, column: 33`},
+		{input: `match (1) { x => fun() { x = 2; } };`, expectedErrorMsg: `cannot reassign constant: "x" at line: 1, column: 26`},
+		{input: `let foo = 1; foo = 2`, expectedErrorMsg: "expected semicolon at line: 1, column: 21"},
+		{input: "const foo = 1;\n\tconst bar \"string\";", expectedErrorMsg: `unexpected token: "STRING" (expected: "=") at line: 2, column: 12`},
+		{input: `const foo = if (true) { 1 };`, expectedErrorMsg: `unexpected token: ";" (expected: "ELSE") at line: 1, column: 28`},
+		{input: `if (true) { 1 }`, expectedErrorMsg: "expected semicolon at line: 1, column: 14"},
+		{input: `break;`, expectedErrorMsg: "break statement not permitted outside loop at line: 1, column: 1"},
+		{input: `for (x in xs) { const f = fun() { continue; }; }`, expectedErrorMsg: "continue statement not permitted outside loop at line: 1, column: 35"},
+		{input: `for (x in xs) { const y = if (x) { break; } else { 1 }; }`, expectedErrorMsg: "break statement not permitted outside loop at line: 1, column: 36"},
+		{input: `for (len in xs) { }`, expectedErrorMsg: `cannot override built-in function: "len" at line: 1, column: 6`},
+		{input: `for (x of xs) { }`, expectedErrorMsg: `unexpected token: "IDENT" (expected: "IN") at line: 1, column: 8`},
+		{input: `const foo = if (true) { fun() { 1 } } else { 2 };`, expectedErrorMsg: "expected semicolon at line: 1, column: 34"},
 	}
 
 	for _, tt := range tests {
@@ -755,8 +1244,285 @@ func TestParserErrors(t *testing.T) {
 
 		p.ParseProgram()
 
-		if p.errors[0] != tt.expectedErrorMsg {
-			t.Errorf("wrong error message, expected: %s, got: %s", tt.expectedErrorMsg, p.errors[0])
+		if p.errors[0].Error() != tt.expectedErrorMsg {
+			t.Errorf("wrong error message, expected: %s, got: %s", tt.expectedErrorMsg, p.errors[0].Error())
+		}
+	}
+}
+
+func TestErrorDiagnostics(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedCode  diagnostic.Code
+		expectedPos   string
+		expectedEnd   string
+		expectedHints []string
+	}{
+		{"const a = 5 $;", diagnostic.IllegalCharacter, "line: 1, column: 13", "line: 1, column: 14", nil},
+		{"const a = 0b12;", diagnostic.MalformedNumber, "line: 1, column: 11", "line: 1, column: 15", nil},
+		{"const a = 5", diagnostic.MissingSemicolon, "line: 1, column: 12", "line: 1, column: 12", []string{`add ";" at the end of the statement`}},
+		{"const a = foo(1 2);", diagnostic.UnexpectedToken, "line: 1, column: 17", "line: 1, column: 18", nil},
+		{"const len = 1;", diagnostic.BuiltinOverride, "line: 1, column: 7", "line: 1, column: 10", []string{"choose a different name"}},
+		{"9223372036854775808;", diagnostic.InvalidNumber, "line: 1, column: 1", "line: 1, column: 20", []string{"use a float literal for larger numbers"}},
+		{"const x = 1; x = 2;", diagnostic.ConstantAssignment, "line: 1, column: 14", "line: 1, column: 15", []string{"declare it with let to make it mutable"}},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Fatalf("tests[%d] - no errors reported", i)
+		}
+		d := p.Errors()[0]
+
+		if d.Severity != diagnostic.Error {
+			t.Errorf("tests[%d] - severity wrong. expected=%s, got=%s", i, diagnostic.Error, d.Severity)
+		}
+		if d.Code != tt.expectedCode {
+			t.Errorf("tests[%d] - code wrong. expected=%q, got=%q", i, tt.expectedCode, d.Code)
+		}
+		if d.Pos.String() != tt.expectedPos {
+			t.Errorf("tests[%d] - pos wrong. expected=%q, got=%q", i, tt.expectedPos, d.Pos)
+		}
+		if d.End.String() != tt.expectedEnd {
+			t.Errorf("tests[%d] - end wrong. expected=%q, got=%q", i, tt.expectedEnd, d.End)
+		}
+		if !reflect.DeepEqual(d.Hints, tt.expectedHints) {
+			t.Errorf("tests[%d] - hints wrong. expected=%q, got=%q", i, tt.expectedHints, d.Hints)
+		}
+	}
+}
+
+func TestParserErrorRecovery(t *testing.T) {
+	input := `const a = foo(1, 2;
+const b = fun(x) {
+	const y = x +;
+	return y
+};
+if (a > 1 {
+	print(a);
+}
+const c = {"a" 1, "b": 2};
+const d = fun() { x + };
+print(d(;
+const e = [1, 2;
+const f = 3 $ + 0b12;
+const g = 4;`
+
+	expectedErrors := []string{
+		`unexpected token: ";" (expected: ")") at line: 1, column: 19`,
+		`unexpected token: ";" at line: 3, column: 15`,
+		`expected semicolon at line: 4, column: 10`,
+		`unexpected token: "{" (expected: ")") at line: 6, column: 11`,
+		`unexpected token: "INT" (expected: ":") at line: 9, column: 16`,
+		`unexpected token: "}" at line: 10, column: 23`,
+		`unexpected token: ";" at line: 11, column: 9`,
+		`unexpected token: ";" (expected: "]") at line: 12, column: 16`,
+		`illegal character: "$" at line: 13, column: 13`,
+		`malformed number literal: "0b12" at line: 13, column: 17`,
+	}
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != len(expectedErrors) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%d (%q)", len(expectedErrors), len(p.Errors()), p.Errors())
+	}
+	for i, err := range p.Errors() {
+		if err.Error() != expectedErrors[i] {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, expectedErrors[i], err.Error())
+		}
+	}
+
+	last := program.Statements[len(program.Statements)-1]
+	if last.String() != "const g = 4;" {
+		t.Errorf("parsing didn't recover before the last statement. got=%q", last.String())
+	}
+}
+
+func TestUnterminatedBlock(t *testing.T) {
+	l := lexer.New("const f = fun() { return 1;")
+	p := New(l)
+	p.ParseProgram()
+
+	expected := `unexpected token: "EOF" (expected: "}") at line: 1, column: 28`
+	if len(p.Errors()) != 1 || p.Errors()[0].Error() != expected {
+		t.Fatalf("errors wrong. expected=[%q], got=%q", expected, p.Errors())
+	}
+}
+
+func TestReportAllLexicalErrors(t *testing.T) {
+	input := `const a = 1;$
+const b = 2 #;
+print(a @+ b);
+print("bad \q");`
+
+	// illegal tokens are dropped, so the statements around them are still valid
+	expectedErrors := []string{
+		`illegal character: "$" at line: 1, column: 13`,
+		`illegal character: "#" at line: 2, column: 13`,
+		`illegal character: "@" at line: 3, column: 9`,
+		`invalid escape sequence: "\q" at line: 4, column: 12`,
+	}
+
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) != len(expectedErrors) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%d (%q)", len(expectedErrors), len(p.Errors()), p.Errors())
+	}
+	for i, err := range p.Errors() {
+		if err.Error() != expectedErrors[i] {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, expectedErrors[i], err.Error())
+		}
+	}
+}
+
+func TestCommentAttachment(t *testing.T) {
+	input := `// first line of the lead comment
+// second line of the lead comment
+const add = fun(x, y) {
+	/* lead of return */
+	return x + y; // trailing of return
+}; // trailing of const
+
+// detached comment
+
+add(1, /* inside */ 2);
+// comment at the end`
+
+	l := lexer.New(input)
+	l.Mode = lexer.ScanComments
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+	}
+
+	expectedGroups := []string{
+		"// first line of the lead comment\n// second line of the lead comment",
+		"/* lead of return */",
+		"// trailing of return",
+		"// trailing of const",
+		"// detached comment",
+		"/* inside */",
+		"// comment at the end",
+	}
+	if len(program.Comments) != len(expectedGroups) {
+		t.Fatalf("wrong number of comment groups. expected=%d, got=%d", len(expectedGroups), len(program.Comments))
+	}
+	for i, group := range program.Comments {
+		if group.String() != expectedGroups[i] {
+			t.Errorf("comments[%d] wrong. expected=%q, got=%q", i, expectedGroups[i], group.String())
+		}
+	}
+
+	constStmnt := program.Statements[0].(*ast.ConstStatement)
+	returnStmnt := constStmnt.Value.(*ast.FunctionLiteral).Body.Statements[0]
+
+	tests := []struct {
+		node     ast.Node
+		expected []string
+	}{
+		{constStmnt, []string{expectedGroups[0], expectedGroups[3]}},
+		{returnStmnt, []string{expectedGroups[1], expectedGroups[2]}},
+		{program.Statements[1], []string{}},
+	}
+
+	for i, tt := range tests {
+		groups := program.CommentMap[tt.node]
+		if len(groups) != len(tt.expected) {
+			t.Fatalf("tests[%d] - wrong number of attached comments. expected=%d, got=%d", i, len(tt.expected), len(groups))
+		}
+		for j, group := range groups {
+			if group.String() != tt.expected[j] {
+				t.Errorf("tests[%d] - comment %d wrong. expected=%q, got=%q", i, j, tt.expected[j], group.String())
+			}
+		}
+	}
+}
+
+func TestDocComments(t *testing.T) {
+	input := `/** Adds two numbers. */
+const add = fun(x, y) { return x + y; };
+
+/** detached by an empty line */
+
+const sub = fun(x, y) { return x - y; };
+
+// not a doc comment
+const mul = fun(x, y) { return x * y; };
+
+// leading note
+/**
+ * Divides two numbers.
+ */
+const div = fun(x, y) { return x / y; };`
+
+	l := lexer.New(input)
+	l.Mode = lexer.ScanComments
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expectedDocs := []string{"Adds two numbers.", "", "", "leading note\nDivides two numbers."}
+	if len(program.Statements) != len(expectedDocs) {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d", len(expectedDocs), len(program.Statements))
+	}
+
+	for i, expected := range expectedDocs {
+		stmnt := program.Statements[i].(*ast.ConstStatement)
+		if expected == "" {
+			if stmnt.Doc != nil {
+				t.Errorf("statements[%d] - unexpected doc comment: %q", i, stmnt.Doc.Text())
+			}
+			continue
+		}
+		if stmnt.Doc == nil {
+			t.Fatalf("statements[%d] - doc comment missing", i)
+		}
+		if stmnt.Doc.Text() != expected {
+			t.Errorf("statements[%d] - doc wrong. expected=%q, got=%q", i, expected, stmnt.Doc.Text())
+		}
+	}
+}
+
+func TestNodePositions(t *testing.T) {
+	input := `const add = fun(x, y) {
+	return x + y;
+};
+add(1, [2][0]);
+const inc = (x) => x + 1;
+obj.name;`
+
+	program := testParsingInput(t, input, 4)
+
+	tests := []struct {
+		node          ast.Node
+		expectedStart string
+		expectedEnd   string
+	}{
+		{program, "line: 1, column: 1", "line: 6, column: 10"},
+		{program.Statements[0], "line: 1, column: 1", "line: 3, column: 3"},
+		{program.Statements[0].(*ast.ConstStatement).Value, "line: 1, column: 13", "line: 3, column: 2"},
+		{program.Statements[1], "line: 4, column: 1", "line: 4, column: 16"},
+		{program.Statements[1].(*ast.ExpressionStatement).Expression, "line: 4, column: 1", "line: 4, column: 15"},
+		{program.Statements[2].(*ast.ConstStatement).Value, "line: 5, column: 13", "line: 5, column: 25"},
+		{program.Statements[3].(*ast.ExpressionStatement).Expression, "line: 6, column: 1", "line: 6, column: 9"},
+	}
+
+	for i, tt := range tests {
+		if tt.node.Pos().String() != tt.expectedStart {
+			t.Errorf("tests[%d] - start position wrong. expected=%q, got=%q", i, tt.expectedStart, tt.node.Pos())
+		}
+		if tt.node.End().String() != tt.expectedEnd {
+			t.Errorf("tests[%d] - end position wrong. expected=%q, got=%q", i, tt.expectedEnd, tt.node.End())
 		}
 	}
 }
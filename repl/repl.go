@@ -6,9 +6,11 @@ import (
 	"io"
 	"os"
 	"os/user"
+	"strings"
 
 	"github.com/radlinskii/interpreter/object"
 
+	"github.com/radlinskii/interpreter/diagnostic"
 	"github.com/radlinskii/interpreter/evaluator"
 
 	"github.com/radlinskii/interpreter/lexer"
@@ -35,11 +37,15 @@ func Start(in io.Reader, out io.Writer) {
 		p := parser.New(l)
 		program := p.ParseProgram()
 
-		if len(p.Errors()) == 0 {
-			evaluated := evaluator.EvalProgram(program, env)
-			fmt.Println(evaluated)
+		if len(p.Errors()) != 0 {
+			diagnostic.Render(out, strings.NewReader(line), p.Errors())
+			continue
 		}
 
+		output, errors := evaluator.EvalProgram(program, env)
+		fmt.Fprintln(out, output)
+		diagnostic.Render(out, strings.NewReader(line), errors)
+
 	}
 }
 
@@ -1,13 +1,37 @@
 package token
 
+import "fmt"
+
 // Type is a token type.
 type Type string
 
+// Position describes a location in the source code.
+type Position struct {
+	Filename string // name of the source file, may be empty
+	Offset   int    // byte offset, starting at 0
+	Line     int    // line number, starting at 1
+	Column   int    // column number in characters, starting at 1
+}
+
+// IsValid reports whether the position has been set.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	s := fmt.Sprintf("line: %d, column: %d", p.Line, p.Column)
+	if p.Filename != "" {
+		s = p.Filename + ", " + s
+	}
+	return s
+}
+
 // Token is the lexical symbol that gets returned after performing lexical analysis.
 type Token struct {
-	Type       Type
-	Literal    string
-	LineNumber int
+	Type    Type
+	Literal string
+	Pos     Position // position of the first character of the token
+	End     Position // position immediately after the token
 }
 
 const (
@@ -17,11 +41,21 @@ const (
 	EOF = "EOF"
 	// IDENT - identifier
 	IDENT = "IDENT"
+	// COMMENT - single or multi line comment, returned only when the lexer is asked to scan comments
+	COMMENT = "COMMENT"
 
 	// INT - integer literal
 	INT = "INT"
+	// FLOAT - floating-point number literal
+	FLOAT = "FLOAT"
 	// STRING - string literal
 	STRING = "STRING"
+	// STRING_HEAD - part of an interpolated string literal from the opening quote to the first "${"
+	STRING_HEAD = "STRING_HEAD"
+	// STRING_MIDDLE - part of an interpolated string literal between "}" and the next "${"
+	STRING_MIDDLE = "STRING_MIDDLE"
+	// STRING_TAIL - part of an interpolated string literal from the last "}" to the closing quote
+	STRING_TAIL = "STRING_TAIL"
 	// BOOLEAN - boolean literal
 	BOOLEAN = "BOOLEAN"
 
@@ -37,6 +71,23 @@ const (
 	ASTERISK = "*"
 	// SLASH - division
 	SLASH = "/"
+	// PERCENT - remainder of division
+	PERCENT = "%"
+	// POWER - exponentiation
+	POWER = "**"
+
+	// BIT_AND - bitwise AND
+	BIT_AND = "&"
+	// BIT_OR - bitwise OR
+	BIT_OR = "|"
+	// BIT_XOR - bitwise XOR
+	BIT_XOR = "^"
+	// BIT_NOT - bitwise complement
+	BIT_NOT = "~"
+	// SHL - left shift
+	SHL = "<<"
+	// SHR - right shift
+	SHR = ">>"
 
 	// LT - lower than
 	LT = "<"
@@ -51,12 +102,25 @@ const (
 	// NEQ - not equal
 	NEQ = "!="
 
+	// AND - logical conjunction
+	AND = "&&"
+	// OR - logical disjunction
+	OR = "||"
+	// PIPE - passes the left operand as the first argument of the function on the right
+	PIPE = "|>"
+
 	// COMMA - values delimeter
 	COMMA = ","
 	// SEMICOLON - separates expressions
 	SEMICOLON = ";"
 	// COLON - separates key value pair in hashes
 	COLON = ":"
+	// ARROW - separates parameters from the body of arrow function
+	ARROW = "=>"
+	// DOT - accesses a field of a hash
+	DOT = "."
+	// ELLIPSIS - gathers the rest of the elements in destructuring patterns
+	ELLIPSIS = "..."
 
 	// LPAREN - function calls, binding expressions
 	LPAREN = "("
@@ -77,20 +141,38 @@ const (
 	RETURN = "RETURN"
 	// CONST keyword "const"
 	CONST = "CONST"
+	// LET keyword "let"
+	LET = "LET"
 	// IF keyword "if"
 	IF = "IF"
 	// ELSE keyword "else"
 	ELSE = "ELSE"
+	// FOR keyword "for"
+	FOR = "FOR"
+	// IN keyword "in"
+	IN = "IN"
+	// BREAK keyword "break"
+	BREAK = "BREAK"
+	// CONTINUE keyword "continue"
+	CONTINUE = "CONTINUE"
+	// MATCH keyword "match"
+	MATCH = "MATCH"
 )
 
 var keywords = map[string]Type{
-	"fun":    FUNCTION,
-	"const":  CONST,
-	"return": RETURN,
-	"true":   BOOLEAN,
-	"false":  BOOLEAN,
-	"if":     IF,
-	"else":   ELSE,
+	"fun":      FUNCTION,
+	"const":    CONST,
+	"let":      LET,
+	"return":   RETURN,
+	"true":     BOOLEAN,
+	"false":    BOOLEAN,
+	"if":       IF,
+	"else":     ELSE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"match":    MATCH,
 }
 
 // LookUpIdent checks if identifier exists in the map of keywords.
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/radlinskii/interpreter/lexer"
+	"github.com/radlinskii/interpreter/token"
+)
+
+const tokensUsage = `Usage: junior tokens [-json] [-comments] <file>
+
+Prints the tokens of the program, "-" reads the program from the standard input.
+`
+
+// runTokens implements the "tokens" command, it returns the exit code of the program.
+func runTokens(args []string) int {
+	flags := flag.NewFlagSet("tokens", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the tokens as a JSON array")
+	comments := flags.Bool("comments", false, "print the comments as COMMENT tokens")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, tokensUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	input, err := openInput(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	defer input.Close()
+
+	l := newLexer(flags.Arg(0), input)
+	if *comments {
+		l.Mode = lexer.ScanComments
+	}
+
+	if *asJSON {
+		err = writeTokensJSON(os.Stdout, l)
+	} else {
+		err = writeTokensTable(os.Stdout, l)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	if len(l.Errors()) != 0 {
+		reportErrors(flags.Arg(0), l.Errors())
+		return 1
+	}
+	return 0
+}
+
+// writeTokensTable prints every token in a separate row with its position, type and literal.
+func writeTokensTable(out io.Writer, l *lexer.Lexer) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tTYPE\tLITERAL")
+
+	for {
+		tok := l.NextToken()
+		fmt.Fprintf(w, "%d:%d\t%s\t%q\n", tok.Pos.Line, tok.Pos.Column, tok.Type, tok.Literal)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	return w.Flush()
+}
+
+type jsonPosition struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type jsonToken struct {
+	Type    token.Type   `json:"type"`
+	Literal string       `json:"literal"`
+	Pos     jsonPosition `json:"pos"`
+	End     jsonPosition `json:"end"`
+}
+
+func newJSONPosition(pos token.Position) jsonPosition {
+	return jsonPosition{Offset: pos.Offset, Line: pos.Line, Column: pos.Column}
+}
+
+// writeTokensJSON prints the tokens as a JSON array, one token per line.
+// Tokens are written as soon as they are read, so the whole stream is never kept in memory.
+func writeTokensJSON(out io.Writer, l *lexer.Lexer) error {
+	if _, err := io.WriteString(out, "[\n"); err != nil {
+		return err
+	}
+
+	for {
+		tok := l.NextToken()
+		data, err := json.Marshal(jsonToken{
+			Type:    tok.Type,
+			Literal: tok.Literal,
+			Pos:     newJSONPosition(tok.Pos),
+			End:     newJSONPosition(tok.End),
+		})
+		if err != nil {
+			return err
+		}
+
+		separator := ",\n"
+		if tok.Type == token.EOF {
+			separator = "\n"
+		}
+		if _, err := fmt.Fprintf(out, "  %s%s", data, separator); err != nil {
+			return err
+		}
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	_, err := io.WriteString(out, "]\n")
+	return err
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/radlinskii/interpreter/lexer"
+)
+
+func TestWriteTokensTable(t *testing.T) {
+	input := `const s = "a \"q\"";`
+
+	expected := `POSITION  TYPE    LITERAL
+1:1       CONST   "const"
+1:7       IDENT   "s"
+1:9       =       "="
+1:11      STRING  "a \"q\""
+1:20      ;       ";"
+1:21      EOF     ""
+`
+
+	var out bytes.Buffer
+	if err := writeTokensTable(&out, lexer.New(input)); err != nil {
+		t.Fatalf("writeTokensTable returned error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("wrong table. expected=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestWriteTokensJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"",
+			"[\n" +
+				`  {"type":"EOF","literal":"","pos":{"offset":0,"line":1,"column":1},"end":{"offset":0,"line":1,"column":1}}` + "\n" +
+				"]\n",
+		},
+		{
+			"x;",
+			"[\n" +
+				`  {"type":"IDENT","literal":"x","pos":{"offset":0,"line":1,"column":1},"end":{"offset":1,"line":1,"column":2}},` + "\n" +
+				`  {"type":";","literal":";","pos":{"offset":1,"line":1,"column":2},"end":{"offset":2,"line":1,"column":3}},` + "\n" +
+				`  {"type":"EOF","literal":"","pos":{"offset":2,"line":1,"column":3},"end":{"offset":2,"line":1,"column":3}}` + "\n" +
+				"]\n",
+		},
+	}
+
+	for i, tt := range tests {
+		var out bytes.Buffer
+		if err := writeTokensJSON(&out, lexer.New(tt.input)); err != nil {
+			t.Fatalf("tests[%d] - writeTokensJSON returned error: %s", i, err)
+		}
+
+		if out.String() != tt.expected {
+			t.Errorf("tests[%d] - wrong JSON. expected=\n%s\ngot=\n%s", i, tt.expected, out.String())
+		}
+	}
+}
+
+func TestWriteTokensJSONEscaping(t *testing.T) {
+	input := "\"quote \\\" backslash \\\\ tab \\t newline \\n <tag> \\u{1F600}\";"
+	expectedLiteral := "quote \" backslash \\ tab \t newline \n <tag> \U0001F600"
+
+	var out bytes.Buffer
+	if err := writeTokensJSON(&out, lexer.New(input)); err != nil {
+		t.Fatalf("writeTokensJSON returned error: %s", err)
+	}
+
+	if strings.Count(out.String(), "\n") != 5 {
+		t.Fatalf("every token is not written in a separate line. got=\n%s", out.String())
+	}
+
+	var tokens []jsonToken
+	if err := json.Unmarshal(out.Bytes(), &tokens); err != nil {
+		t.Fatalf("output is not valid JSON: %s\n%s", err, out.String())
+	}
+
+	if len(tokens) != 3 {
+		t.Fatalf("wrong number of tokens. expected=3, got=%d", len(tokens))
+	}
+
+	if tokens[0].Literal != expectedLiteral {
+		t.Errorf("wrong literal. expected=%q, got=%q", expectedLiteral, tokens[0].Literal)
+	}
+}